@@ -0,0 +1,96 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// CannedACL is a predefined access policy for an object.
+type CannedACL string
+
+const (
+	ACLPrivate    CannedACL = "private"
+	ACLPublicRead CannedACL = "public-read"
+)
+
+// ACLGrant gives a grantee a permission such as "read" or "write".
+type ACLGrant struct {
+	Grantee    string `json:"grantee"`
+	Permission string `json:"permission"`
+}
+
+// ACL is the access control list of an object: a canned policy plus optional
+// additional grants.
+type ACL struct {
+	Canned CannedACL  `json:"canned"`
+	Grants []ACLGrant `json:"grants,omitempty"`
+}
+
+// GetObjectACL returns the access control list of an object. It returns
+// ErrNotSupported unless Capabilities has reported ACL support.
+func (c *Client) GetObjectACL(bucket, key string) (*ACL, error) {
+	if err := c.requireConfirmedCapability(supportsACL); err != nil {
+		return nil, err
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "GET", "objects", bucket, key, "acl", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do("GetObjectACL", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.newError(resp)
+	}
+
+	var acl ACL
+	if err := json.NewDecoder(resp.Body).Decode(&acl); err != nil {
+		return nil, err
+	}
+
+	return &acl, nil
+}
+
+// PutObjectACL replaces the access control list of an object. Its content
+// and metadata are not changed. It returns ErrNotSupported unless
+// Capabilities has reported ACL support, since a server without it would
+// store the ACL as the object's content.
+func (c *Client) PutObjectACL(bucket, key string, acl ACL) error {
+	if err := c.requireConfirmedCapability(supportsACL); err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(acl)
+	if err != nil {
+		return err
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "PUT", "objects", bucket, key, "acl", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do("PutObjectACL", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return c.newError(resp)
+	}
+
+	return nil
+}
+
+func supportsACL(s *ServerCapabilities) bool {
+	return s.ACL
+}
@@ -0,0 +1,84 @@
+package objectstorage
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetObjectACL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/buckets/test-bucket/objects/test-key", r.URL.Path)
+		assert.Equal(t, "acl", r.URL.RawQuery)
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(ACL{
+			Canned: ACLPrivate,
+			Grants: []ACLGrant{{Grantee: "user-1", Permission: "read"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{ACL: true})
+	acl, err := client.GetObjectACL("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, ACLPrivate, acl.Canned)
+	assert.Equal(t, []ACLGrant{{Grantee: "user-1", Permission: "read"}}, acl.Grants)
+}
+
+func TestPutObjectACL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "acl", r.URL.RawQuery)
+
+		var acl ACL
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&acl))
+		assert.Equal(t, ACLPublicRead, acl.Canned)
+		assert.Empty(t, acl.Grants)
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{ACL: true})
+	require.NoError(t, client.PutObjectACL("test-bucket", "test-key", ACL{Canned: ACLPublicRead}))
+}
+
+func TestHeadObjectACL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-object-acl", "public-read")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.HeadObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, ACLPublicRead, obj.ACL)
+}
+
+func TestObjectACLRequiresCapability(t *testing.T) {
+	// memObjectServer ignores ?acl like the real server: a PUT would replace
+	// the object with the ACL.
+	server := newMemObjectServer(t)
+	defer server.Close()
+	server.put("test-bucket/test-key", "hello", nil)
+
+	client := NewClient(server.URL)
+	assert.ErrorIs(t, client.PutObjectACL("test-bucket", "test-key", ACL{Canned: ACLPublicRead}), ErrNotSupported)
+	_, err := client.GetObjectACL("test-bucket", "test-key")
+	assert.ErrorIs(t, err, ErrNotSupported)
+
+	client.capabilities.Store(&ServerCapabilities{Tagging: true})
+	assert.ErrorIs(t, client.PutObjectACL("test-bucket", "test-key", ACL{Canned: ACLPublicRead}), ErrNotSupported)
+
+	assert.Empty(t, server.requests)
+	assert.Equal(t, "hello", string(server.get("test-bucket/test-key").data))
+}
@@ -0,0 +1,48 @@
+package objectstorage
+
+import (
+	"net/url"
+	"strings"
+)
+
+// WithVirtualHostedStyle addresses object and listing requests with the
+// bucket in the host name (https://bucket.example.com/objects/key) instead
+// of the path (https://example.com/buckets/bucket/objects/key), as some
+// S3-compatible services require. Buckets whose names are not valid DNS
+// labels fall back to path-style addressing. Bucket management calls always
+// use path-style addressing.
+func WithVirtualHostedStyle() Option {
+	return func(c *Client) {
+		c.virtualHosted = true
+	}
+}
+
+// bucketURL returns the URL under which the object endpoints of bucket live.
+func (c *Client) bucketURL(bucket string) string {
+	if c.virtualHosted && isDNSSafeBucket(bucket) {
+		if u, err := url.Parse(c.baseURL); err == nil && u.Host != "" {
+			u.Host = bucket + "." + u.Host
+			return strings.TrimSuffix(u.String(), "/")
+		}
+	}
+	return c.baseURL + "/buckets/" + bucket
+}
+
+// isDNSSafeBucket reports whether bucket can be used as a single DNS label:
+// 3 to 63 lowercase letters, digits and hyphens, starting and ending with a
+// letter or digit. Dots are rejected because they break wildcard TLS
+// certificates.
+func isDNSSafeBucket(bucket string) bool {
+	if len(bucket) < 3 || len(bucket) > 63 {
+		return false
+	}
+	for i, r := range bucket {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case r == '-' && i > 0 && i < len(bucket)-1:
+		default:
+			return false
+		}
+	}
+	return true
+}
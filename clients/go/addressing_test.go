@@ -0,0 +1,72 @@
+package objectstorage
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// recordingClient returns an http.Client that answers every request with an
+// empty JSON object and records the requested URLs.
+func recordingClient(urls *[]string) *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		*urls = append(*urls, req.Method+" "+req.URL.String())
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(`{}`)),
+			Request:    req,
+		}, nil
+	})}
+}
+
+func TestAddressingStyles(t *testing.T) {
+	var urls []string
+	path := NewClientWithHTTP("https://storage.example.com", recordingClient(&urls))
+	virtual := NewClientWithHTTP("https://storage.example.com", recordingClient(&urls), WithVirtualHostedStyle())
+
+	for _, client := range []*Client{path, virtual} {
+		_, err := client.GetObject("my-bucket", "dir/key")
+		require.NoError(t, err)
+		_, err = client.ListObjects("my-bucket", nil, nil)
+		require.NoError(t, err)
+		// Not a valid DNS label, so always path style.
+		_, err = client.GetObject("My_Bucket", "key")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{
+		"GET https://storage.example.com/buckets/my-bucket/objects/dir/key",
+		"GET https://storage.example.com/buckets/my-bucket/objects",
+		"GET https://storage.example.com/buckets/My_Bucket/objects/key",
+		"GET https://my-bucket.storage.example.com/objects/dir/key",
+		"GET https://my-bucket.storage.example.com/objects",
+		"GET https://storage.example.com/buckets/My_Bucket/objects/key",
+	}, urls)
+}
+
+func TestIsDNSSafeBucket(t *testing.T) {
+	for name, want := range map[string]bool{
+		"my-bucket":             true,
+		"abc":                   true,
+		"ab":                    false,
+		"-bucket":               false,
+		"bucket-":               false,
+		"My-Bucket":             false,
+		"my.bucket":             false,
+		"my_bucket":             false,
+		strings.Repeat("a", 64): false,
+	} {
+		assert.Equal(t, want, isDNSSafeBucket(name), name)
+	}
+}
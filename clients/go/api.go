@@ -0,0 +1,114 @@
+package objectstorage
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// API is the set of methods implemented by *Client. Code that depends on API
+// rather than *Client can be tested with a fake. Every exported method of
+// *Client is part of API.
+type API interface {
+	// Buckets
+	CreateBucket(name string) (*Bucket, error)
+	CreateBucketInRegion(name, region string) (*Bucket, error)
+	UpsertBucket(name string) (*Bucket, error)
+	GetBucket(id string) (*Bucket, error)
+	GetBucketConfig(name string) (*BucketConfig, error)
+	ListBuckets() ([]Bucket, error)
+	ListBucketsWithPrefix(prefix string) ([]Bucket, error)
+	DeleteBucket(name string) error
+	RenameBucket(oldName, newName string) error
+	RenameBucketByCopy(oldName, newName string, opts RenameOptions) (*RenameReport, error)
+
+	// Uploads
+	PutObject(bucket, key string, data []byte, contentType *string, metadata map[string]string, opts ...RequestOption) (*ObjectMetadata, error)
+	PutObjectCT(bucket, key string, data []byte, contentType string, metadata map[string]string, opts ...RequestOption) (*ObjectMetadata, error)
+	PutObjectStream(bucket, key string, r io.Reader, size int64, contentType *string, metadata map[string]string) (*ObjectMetadata, error)
+	PutObjectStreamCT(bucket, key string, r io.Reader, size int64, contentType string, metadata map[string]string) (*ObjectMetadata, error)
+	PutObjectReaderAt(bucket, key string, r io.ReaderAt, size int64, contentType *string, metadata map[string]string) (*ObjectMetadata, error)
+	PutObjectContentAddressed(bucket string, r io.Reader, prefix string, contentType *string, metadata map[string]string) (string, *ObjectMetadata, error)
+	AppendObject(bucket, key string, data []byte, offset uint64) (*ObjectMetadata, error)
+	CreateMultipartUpload(bucket, key string, contentType *string, metadata map[string]string, opts ...RequestOption) (*MultipartUpload, error)
+	UploadPart(bucket, key, uploadID string, partNumber int, data []byte, opts ...RequestOption) (*CompletedPart, error)
+	CompleteMultipartUpload(bucket, key, uploadID string, parts []CompletedPart) (*ObjectMetadata, error)
+	AbortMultipartUpload(bucket, key, uploadID string) error
+	PutObjectIfLarger(bucket, key string, data []byte, contentType *string, metadata map[string]string, opts ...RequestOption) (*ObjectMetadata, error)
+	TransformObject(bucket, srcKey, dstKey string, transform func(io.Reader, io.Writer) error) error
+
+	// Downloads
+	GetObject(bucket, key string, opts ...RequestOption) (*ObjectData, error)
+	GetObjectRange(bucket, key string, start, end int64, opts ...RequestOption) (*ObjectData, error)
+	GetObjectRanges(bucket, key string, ranges [][2]int64, opts ...RequestOption) ([]RangePart, error)
+	NewObjectReaderAt(bucket, key string) (*ObjectReaderAt, int64, error)
+	OpenObject(bucket, key string) (io.ReadSeekCloser, int64, error)
+	NewResumableDownload(bucket, key, path string, checkpoint DownloadCheckpoint) *ResumableDownload
+	ExtractTarGz(bucket, key, destDir string) error
+
+	// Metadata
+	HeadObject(bucket, key string, opts ...RequestOption) (*ObjectMetadata, error)
+	HeadObjects(bucket string, keys []string, concurrency int) (map[string]*ObjectMetadata, map[string]error)
+	HeadObjectsContext(ctx context.Context, bucket string, keys []string, concurrency int) (map[string]*ObjectMetadata, map[string]error)
+	ObjectExists(bucket, key string) (bool, error)
+	ObjectsExist(bucket string, keys []string, concurrency int) (map[string]bool, map[string]error)
+	ObjectsExistContext(ctx context.Context, bucket string, keys []string, concurrency int) (map[string]bool, map[string]error)
+	GetObjectInfo(bucket, key string, opts ...RequestOption) (*ObjectMetadata, error)
+	GetObjectInfoRaw(bucket, key string) (json.RawMessage, *ObjectMetadata, error)
+	UpdateObjectMetadata(bucket, key string, contentType *string, metadata map[string]string) (*ObjectMetadata, error)
+	UpdateObjectMetadataCT(bucket, key string, contentType string, metadata map[string]string) (*ObjectMetadata, error)
+	PatchObjectMetadata(bucket, key string, updates map[string]string, remove []string) (*ObjectMetadata, error)
+	CopyMetadata(bucket, srcKey string, dstKeys []string) (map[string]error, error)
+	TouchObject(bucket, key string) (*ObjectMetadata, error)
+	WaitForObject(ctx context.Context, bucket, key string, poll time.Duration) (*ObjectMetadata, error)
+	WaitForObjectFunc(ctx context.Context, bucket, key string, poll time.Duration, ready func(*ObjectMetadata) bool) (*ObjectMetadata, error)
+
+	// Copies, moves and deletes
+	CopyObject(srcBucket, srcKey, dstBucket, dstKey string, opts ...RequestOption) (*CopyResult, error)
+	MoveObject(srcBucket, srcKey, dstBucket, dstKey string) (*MoveResult, error)
+	DeleteObject(bucket, key string, opts ...RequestOption) error
+
+	// Listings
+	List(bucket string, opts ListOptions) (*ListResult, error)
+	ListObjects(bucket string, prefix *string, maxKeys *int) ([]ObjectMetadata, error)
+	ListObjectsFrom(bucket string, prefix *string, maxKeys *int, token ContinuationToken) (*ListResult, error)
+	ListObjectsIfChanged(bucket string, prefix *string, maxKeys *int, etag string) (*ListResult, error)
+	ListObjectsSince(bucket string, prefix *string, since time.Time) ([]ObjectMetadata, error)
+	ForEachObject(ctx context.Context, bucket string, prefix *string, fn func(ObjectMetadata) error) error
+	PrefixSize(bucket string, prefix string) (totalBytes uint64, objectCount int, err error)
+	PrefixSizeContext(ctx context.Context, bucket string, prefix string) (totalBytes uint64, objectCount int, err error)
+	ListObjectVersions(bucket string, prefix *string) ([]ObjectVersion, error)
+	ListDeleteMarkers(bucket string, prefix *string) ([]ObjectVersion, error)
+	ListMultipartUploads(bucket, prefix string) ([]MultipartUpload, error)
+	ListMultipartUploadsForKey(bucket, key string) ([]MultipartUpload, error)
+	SyncPrefix(bucket, prefix, localDir string, opts SyncOptions) (*SyncReport, error)
+
+	// Access control, tags, retention and storage classes
+	GetObjectACL(bucket, key string) (*ACL, error)
+	PutObjectACL(bucket, key string, acl ACL) error
+	GetObjectTags(bucket, key string) (map[string]string, error)
+	PutObjectTags(bucket, key string, tags map[string]string) error
+	TagPrefix(bucket, prefix string, tags map[string]string, concurrency int) (map[string]error, error)
+	GetObjectLegalHold(bucket, key string) (bool, error)
+	PutObjectLegalHold(bucket, key string, on bool) error
+	TransitionStorageClass(bucket, key, class string) error
+	RestoreObject(bucket, key string, days int) error
+	GetRestoreStatus(bucket, key string) (*RestoreStatus, error)
+	WaitForRestore(ctx context.Context, bucket, key string, poll time.Duration) error
+
+	// Public URLs
+	GetPublicURL(bucket, key string, expirationSecs *uint64, purpose *PublicUrlPurpose) (*PublicURLResponse, error)
+	GetPublicURLs(bucket string, keys []string, expirationSecs *uint64, purpose *PublicUrlPurpose) (map[string]PublicURLResponse, map[string]error)
+
+	// Client state
+	Ping() error
+	Capabilities() (*ServerCapabilities, error)
+	PingContext(ctx context.Context) error
+	Stats() map[string]OperationStats
+	ConnStats() ConnStats
+	RateLimitStatus() (remaining int, resetAt time.Time)
+	Close() error
+}
+
+var _ API = (*Client)(nil)
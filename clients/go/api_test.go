@@ -0,0 +1,22 @@
+package objectstorage
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestAPIInSync fails when an exported method is added to *Client without
+// being added to API.
+func TestAPIInSync(t *testing.T) {
+	api := reflect.TypeOf((*API)(nil)).Elem()
+	client := reflect.TypeOf((*Client)(nil))
+
+	for i := 0; i < client.NumMethod(); i++ {
+		name := client.Method(i).Name
+		_, ok := api.MethodByName(name)
+		assert.True(t, ok, "(*Client).%s is missing from API", name)
+	}
+	assert.Equal(t, client.NumMethod(), api.NumMethod())
+}
@@ -0,0 +1,86 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// AppendObject appends data to an appendable object, creating it if offset
+// is 0 and it does not exist. offset must equal the object's current size;
+// otherwise nothing is written and an error matching ErrPreconditionFailed is
+// returned, so concurrent appenders cannot interleave. The returned metadata
+// carries the new size, which is the offset for the next append.
+//
+// It returns ErrNotSupported if the server does not implement appends.
+func (c *Client) AppendObject(bucket, key string, data []byte, offset uint64) (*ObjectMetadata, error) {
+	if err := c.requireCapability(func(s *ServerCapabilities) bool { return s.Append }); err != nil {
+		return nil, err
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "POST", "objects", bucket, key, "append&position="+strconv.FormatUint(offset, 10), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := c.do("AppendObject", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusConflict:
+		// Some servers report a position mismatch as a conflict.
+		return nil, fmt.Errorf("%w: %w", ErrPreconditionFailed, c.newError(resp))
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		drainBody(resp)
+		return nil, ErrNotSupported
+	default:
+		return nil, c.newError(resp)
+	}
+
+	var objMetadata ObjectMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&objMetadata); err != nil {
+		return nil, err
+	}
+	objMetadata.RequestID = resp.Header.Get("X-Request-Id")
+
+	return &objMetadata, nil
+}
+
+// PutObjectIfLarger uploads data only if it is strictly larger than the
+// object currently stored at key, or if there is none, so that a stale or
+// truncated copy never replaces a grown one, as with rotated logs. Otherwise
+// nothing is sent and the stored object's metadata is returned with Skipped
+// set.
+//
+// The check is best-effort. The upload is sent with If-Match on the ETag
+// that was checked, or If-None-Match when there was no object, and servers
+// that honour those fail it with ErrPreconditionFailed after a concurrent
+// write. This project's server ignores them, so there a writer that stores
+// key between the check and the upload can still be overwritten.
+func (c *Client) PutObjectIfLarger(bucket, key string, data []byte, contentType *string, metadata map[string]string, opts ...RequestOption) (*ObjectMetadata, error) {
+	current, err := c.headObject(context.Background(), "PutObjectIfLarger", bucket, key)
+	switch {
+	case err == nil:
+		if uint64(len(data)) <= current.Size {
+			current.Skipped = true
+			return current, nil
+		}
+		if current.ETag != "" {
+			opts = append(opts, WithIfMatch(current.ETag))
+		}
+	case isNotFound(err):
+		opts = append(opts, WithIfAbsent())
+	default:
+		return nil, err
+	}
+
+	return c.PutObject(bucket, key, data, contentType, metadata, opts...)
+}
@@ -0,0 +1,125 @@
+package objectstorage
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendObject(t *testing.T) {
+	var mu sync.Mutex
+	var content []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/buckets/test-bucket/objects/log", r.URL.Path)
+		_, ok := r.URL.Query()["append"]
+		assert.True(t, ok)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if r.URL.Query().Get("position") != strconv.Itoa(len(content)) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		content = append(content, data...)
+		json.NewEncoder(w).Encode(ObjectMetadata{Key: "log", Size: uint64(len(content))})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.AppendObject("test-bucket", "log", []byte("line 1\n"), 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), obj.Size)
+
+	obj, err = client.AppendObject("test-bucket", "log", []byte("line 2\n"), obj.Size)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(14), obj.Size)
+
+	_, err = client.AppendObject("test-bucket", "log", []byte("stale\n"), 7)
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+	assert.Equal(t, "line 1\nline 2\n", string(content))
+}
+
+func TestAppendObjectConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("position does not match object length"))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).AppendObject("test-bucket", "log", []byte("x"), 3)
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+	var objErr *Error
+	require.True(t, errors.As(err, &objErr))
+	assert.Equal(t, http.StatusConflict, objErr.StatusCode)
+}
+
+func TestAppendObjectNotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).AppendObject("test-bucket", "log", []byte("x"), 0)
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestPutObjectIfLarger(t *testing.T) {
+	var stored []byte
+	var conditions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		etag := `"` + ComputeETag(stored) + `"`
+		switch r.Method {
+		case "HEAD":
+			if stored == nil {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Content-Length", strconv.Itoa(len(stored)))
+		case "PUT":
+			if m := r.Header.Get("If-Match"); m != "" {
+				conditions = append(conditions, "match")
+				if m != etag {
+					w.WriteHeader(http.StatusPreconditionFailed)
+					return
+				}
+			}
+			if r.Header.Get("If-None-Match") == "*" {
+				conditions = append(conditions, "absent")
+			}
+			stored, _ = io.ReadAll(r.Body)
+			json.NewEncoder(w).Encode(ObjectMetadata{Key: "app.log", Size: uint64(len(stored))})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.PutObjectIfLarger("test-bucket", "app.log", []byte("line1\n"), nil, nil)
+	require.NoError(t, err)
+	assert.False(t, obj.Skipped)
+
+	obj, err = client.PutObjectIfLarger("test-bucket", "app.log", []byte("line1\nline2\n"), nil, nil)
+	require.NoError(t, err)
+	assert.False(t, obj.Skipped)
+	assert.Equal(t, uint64(12), obj.Size)
+
+	// Same size and smaller uploads are skipped.
+	for _, data := range []string{"line1\nline3\n", "line1\n"} {
+		obj, err = client.PutObjectIfLarger("test-bucket", "app.log", []byte(data), nil, nil)
+		require.NoError(t, err)
+		assert.True(t, obj.Skipped)
+		assert.Equal(t, uint64(12), obj.Size)
+	}
+	assert.Equal(t, "line1\nline2\n", string(stored))
+	assert.Equal(t, []string{"absent", "match"}, conditions)
+}
@@ -0,0 +1,147 @@
+package objectstorage
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultBatchConcurrency bounds the number of in-flight requests issued by
+// batch helpers that do not take an explicit concurrency.
+const defaultBatchConcurrency = 8
+
+// runConcurrent calls fn for every key using at most concurrency goroutines
+// and waits for all of them to finish.
+func runConcurrent(keys []string, concurrency int, fn func(key string)) {
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		key := key
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(key)
+		}()
+	}
+	wg.Wait()
+}
+
+// GetPublicURLs generates public URLs for many keys concurrently. Each key
+// appears in exactly one of the returned maps.
+func (c *Client) GetPublicURLs(bucket string, keys []string, expirationSecs *uint64, purpose *PublicUrlPurpose) (map[string]PublicURLResponse, map[string]error) {
+	results := make(map[string]PublicURLResponse, len(keys))
+	errs := make(map[string]error)
+	var mu sync.Mutex
+
+	runConcurrent(keys, defaultBatchConcurrency, func(key string) {
+		resp, err := c.GetPublicURL(bucket, key, expirationSecs, purpose)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[key] = err
+			return
+		}
+		results[key] = *resp
+	})
+
+	return results, errs
+}
+
+// HeadObjects fetches the metadata of many objects with at most concurrency
+// HEAD requests in flight. Each key appears in exactly one of the returned
+// maps.
+func (c *Client) HeadObjects(bucket string, keys []string, concurrency int) (map[string]*ObjectMetadata, map[string]error) {
+	return c.HeadObjectsContext(context.Background(), bucket, keys, concurrency)
+}
+
+// HeadObjectsContext is HeadObjects with a context. Once ctx is done,
+// in-flight requests are aborted and remaining keys fail with ctx.Err().
+func (c *Client) HeadObjectsContext(ctx context.Context, bucket string, keys []string, concurrency int) (map[string]*ObjectMetadata, map[string]error) {
+	results := make(map[string]*ObjectMetadata, len(keys))
+	errs := make(map[string]error)
+	var mu sync.Mutex
+
+	runConcurrent(keys, concurrency, func(key string) {
+		var obj *ObjectMetadata
+		err := ctx.Err()
+		if err == nil {
+			obj, err = c.headObject(ctx, "HeadObjects", bucket, key)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[key] = err
+			return
+		}
+		results[key] = obj
+	})
+
+	return results, errs
+}
+
+// ObjectsExist checks which of keys exist with at most concurrency HEAD
+// requests in flight. Each key appears in exactly one of the returned maps:
+// missing objects are reported as false, other failures as errors.
+func (c *Client) ObjectsExist(bucket string, keys []string, concurrency int) (map[string]bool, map[string]error) {
+	return c.ObjectsExistContext(context.Background(), bucket, keys, concurrency)
+}
+
+// ObjectsExistContext is ObjectsExist with a context. Once ctx is done,
+// in-flight requests are aborted and remaining keys fail with ctx.Err().
+func (c *Client) ObjectsExistContext(ctx context.Context, bucket string, keys []string, concurrency int) (map[string]bool, map[string]error) {
+	results := make(map[string]bool, len(keys))
+	errs := make(map[string]error)
+	var mu sync.Mutex
+
+	runConcurrent(keys, concurrency, func(key string) {
+		var exists bool
+		err := ctx.Err()
+		if err == nil {
+			exists, err = c.objectExists(ctx, "ObjectsExist", bucket, key)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[key] = err
+			return
+		}
+		results[key] = exists
+	})
+
+	return results, errs
+}
+
+// CopyMetadata applies the content type and user metadata of bucket/srcKey
+// to each of dstKeys with metadata-only updates; object data is not copied.
+// The returned error is set if the source could not be read, in which case
+// nothing was updated. Otherwise the map holds the keys whose update failed.
+func (c *Client) CopyMetadata(bucket, srcKey string, dstKeys []string) (map[string]error, error) {
+	src, err := c.GetObjectInfo(bucket, srcKey)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make(map[string]error)
+	var mu sync.Mutex
+
+	runConcurrent(dstKeys, defaultBatchConcurrency, func(key string) {
+		_, err := c.UpdateObjectMetadata(bucket, key, src.ContentType, src.Metadata)
+		if err == nil {
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		errs[key] = err
+	})
+
+	return errs, nil
+}
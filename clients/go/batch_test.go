@@ -0,0 +1,158 @@
+package objectstorage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPublicURLs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/buckets/test-bucket/public-url/")
+		assert.Equal(t, "retrieve", r.URL.Query().Get("purpose"))
+		if key == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("Object not found"))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(PublicURLResponse{
+			URL:       "https://example.com/" + key,
+			ExpiresIn: 3600,
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	purpose := PublicUrlPurposeRetrieve
+	urls, errs := client.GetPublicURLs("test-bucket", []string{"a.png", "b.png", "missing"}, nil, &purpose)
+
+	assert.Len(t, urls, 2)
+	assert.Equal(t, "https://example.com/a.png", urls["a.png"].URL)
+	assert.Equal(t, "https://example.com/b.png", urls["b.png"].URL)
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs["missing"].Error(), "404")
+}
+
+func TestHeadObjects(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		assert.Equal(t, "HEAD", r.Method)
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", "42")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	keys := []string{"a", "b", "c", "d", "e", "missing"}
+	objs, errs := client.HeadObjects("test-bucket", keys, 2)
+
+	assert.Len(t, objs, 5)
+	assert.Equal(t, uint64(42), objs["c"].Size)
+	assert.Equal(t, "c", objs["c"].Key)
+	assert.Len(t, errs, 1)
+	assert.Error(t, errs["missing"])
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(2))
+}
+
+func TestHeadObjectsContextCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	client := NewClient(server.URL)
+	objs, errs := client.HeadObjectsContext(ctx, "test-bucket", []string{"a", "b"}, 1)
+	assert.Empty(t, objs)
+	assert.ErrorIs(t, errs["a"], context.Canceled)
+	assert.ErrorIs(t, errs["b"], context.Canceled)
+}
+
+func TestObjectsExist(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "HEAD", r.Method)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/missing"):
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasSuffix(r.URL.Path, "/forbidden"):
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	exists, errs := client.ObjectsExist("test-bucket", []string{"a", "b", "missing", "forbidden"}, 2)
+	assert.Equal(t, map[string]bool{"a": true, "b": true, "missing": false}, exists)
+	require.Len(t, errs, 1)
+	var objErr *Error
+	require.ErrorAs(t, errs["forbidden"], &objErr)
+	assert.Equal(t, http.StatusForbidden, objErr.StatusCode)
+
+	ok, err := client.ObjectExists("test-bucket", "missing")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	exists, errs = client.ObjectsExistContext(ctx, "test-bucket", []string{"a"}, 1)
+	assert.Empty(t, exists)
+	assert.ErrorIs(t, errs["a"], context.Canceled)
+}
+
+func TestCopyMetadata(t *testing.T) {
+	var mu sync.Mutex
+	updated := map[string]updateObjectMetadataRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/buckets/test-bucket/object-info/")
+		switch {
+		case r.Method == "GET":
+			assert.Equal(t, "src", key)
+			ct := "image/png"
+			json.NewEncoder(w).Encode(ObjectMetadata{Key: key, ContentType: &ct, Metadata: map[string]string{"owner": "alice"}})
+		case key == "missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			var req updateObjectMetadataRequest
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			mu.Lock()
+			updated[key] = req
+			mu.Unlock()
+			json.NewEncoder(w).Encode(ObjectMetadata{Key: key})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	errs, err := client.CopyMetadata("test-bucket", "src", []string{"a", "b", "missing"})
+	require.NoError(t, err)
+	assert.Len(t, errs, 1)
+	assert.Error(t, errs["missing"])
+
+	assert.Len(t, updated, 2)
+	assert.Equal(t, "image/png", *updated["a"].ContentType)
+	assert.Equal(t, map[string]string{"owner": "alice"}, updated["b"].Metadata)
+}
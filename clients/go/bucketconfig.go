@@ -0,0 +1,197 @@
+package objectstorage
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// BucketConfig aggregates the configuration of a bucket, as returned by
+// GetBucketConfig.
+type BucketConfig struct {
+	Bucket Bucket
+	// Versioning is the versioning status, such as "enabled" or "suspended",
+	// or empty if versioning was never configured.
+	Versioning string
+	// Encryption is the default encryption applied to new objects, or nil if
+	// none is configured.
+	Encryption *BucketEncryption
+	// ObjectLock is the object-lock configuration, or nil if none is
+	// configured.
+	ObjectLock *ObjectLockConfig
+	Tags       map[string]string
+	// Unsupported names the subresources ("versioning", "encryption",
+	// "object-lock", "tagging") that could not be read because the server
+	// does not implement them or has not reported them through
+	// Capabilities. Their fields are left at the zero value, which then
+	// means unknown rather than not configured.
+	Unsupported []string
+}
+
+// BucketEncryption is a bucket's default encryption.
+type BucketEncryption struct {
+	Algorithm string `json:"algorithm"`
+	KMSKeyID  string `json:"kms_key_id,omitempty"`
+}
+
+// ObjectLockConfig is a bucket's object-lock configuration.
+type ObjectLockConfig struct {
+	Enabled bool `json:"enabled"`
+	// Mode and RetentionDays describe the default retention applied to new
+	// objects, if any.
+	Mode          string `json:"mode,omitempty"`
+	RetentionDays int    `json:"retention_days,omitempty"`
+}
+
+type bucketVersioning struct {
+	Status string `json:"status"`
+}
+
+// GetBucketConfig fetches the bucket named name and its versioning, default
+// encryption, object-lock and tagging subresources concurrently and combines
+// them.
+//
+// A server without a subresource answers it with 404 like an unconfigured
+// one, so a subresource is only read once Capabilities has reported the
+// matching Versioning, Encryption, ObjectLock or Tagging feature; a 404 then
+// means not configured. Subresources that are not reported, or that the
+// server answers with 405 or 501, are listed in Unsupported instead of
+// failing the call. Any other failure is returned.
+func (c *Client) GetBucketConfig(name string) (*BucketConfig, error) {
+	config := &BucketConfig{}
+	var versioning bucketVersioning
+	var tagging objectTagging
+	var encryption BucketEncryption
+	var objectLock ObjectLockConfig
+
+	fetches := []struct {
+		subresource string
+		has         func(*ServerCapabilities) bool
+		v           interface{}
+		found       bool
+	}{
+		{subresource: "versioning", has: supportsVersioning, v: &versioning},
+		{subresource: "encryption", has: supportsEncryption, v: &encryption},
+		{subresource: "object-lock", has: supportsObjectLock, v: &objectLock},
+		{subresource: "tagging", has: supportsTagging, v: &tagging},
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	wg.Add(len(fetches) + 1)
+	go func() {
+		defer wg.Done()
+		bucket, err := c.bucketByName(name)
+		if err != nil {
+			fail(err)
+			return
+		}
+		config.Bucket = *bucket
+	}()
+	for i := range fetches {
+		f := &fetches[i]
+		go func() {
+			defer wg.Done()
+			err := c.requireConfirmedCapability(f.has)
+			var found bool
+			if err == nil {
+				found, err = c.getBucketSubresource(name, f.subresource, f.v)
+			}
+			if err == ErrNotSupported {
+				mu.Lock()
+				defer mu.Unlock()
+				config.Unsupported = append(config.Unsupported, f.subresource)
+				return
+			}
+			if err != nil {
+				fail(err)
+				return
+			}
+			f.found = found
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	sort.Strings(config.Unsupported)
+	config.Versioning = versioning.Status
+	if fetches[1].found {
+		config.Encryption = &encryption
+	}
+	if fetches[2].found {
+		config.ObjectLock = &objectLock
+	}
+	config.Tags = tagging.Tags
+	if config.Tags == nil {
+		config.Tags = map[string]string{}
+	}
+	return config, nil
+}
+
+// bucketByName finds the bucket called name. GetBucket cannot be used, as the
+// server looks buckets up by id there. A missing bucket is reported as a 404
+// *Error.
+func (c *Client) bucketByName(name string) (*Bucket, error) {
+	buckets, err := c.ListBucketsWithPrefix(name)
+	if err != nil {
+		return nil, err
+	}
+	for i := range buckets {
+		if buckets[i].Name == name {
+			return &buckets[i], nil
+		}
+	}
+	return nil, &Error{StatusCode: http.StatusNotFound, Message: "Bucket not found: " + name}
+}
+
+// getBucketSubresource decodes GET /buckets/{name}/{subresource} into v. It
+// reports false without error on 404, which the caller must only take to
+// mean not configured if the server has reported the subresource, and
+// returns ErrNotSupported on 405 or 501.
+func (c *Client) getBucketSubresource(name, subresource string, v interface{}) (bool, error) {
+	req, err := http.NewRequest("GET", c.bucketURL(name)+"/"+subresource, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := c.do("GetBucketConfig", req)
+	if err != nil {
+		return false, err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		drainBody(resp)
+		return false, nil
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		drainBody(resp)
+		return false, ErrNotSupported
+	default:
+		defer resp.Body.Close()
+		return false, c.newError(resp)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func supportsEncryption(s *ServerCapabilities) bool {
+	return s.Encryption
+}
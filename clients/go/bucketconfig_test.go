@@ -0,0 +1,77 @@
+package objectstorage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBucketConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		switch r.URL.Path {
+		case "/buckets":
+			// As on the server, buckets are looked up by name only through
+			// the listing; /buckets/{id} takes an id.
+			w.Write([]byte(`{"buckets":[{"id":"b0","name":"audit-old"},{"id":"b1","name":"audit","region":"eu"}]}`))
+		case "/buckets/b1":
+			w.Write([]byte(`{"id":"b1","name":"audit","region":"eu"}`))
+		case "/buckets/audit/versioning":
+			w.Write([]byte(`{"status":"enabled"}`))
+		case "/buckets/audit/encryption":
+			w.Write([]byte(`{"algorithm":"aws:kms","kms_key_id":"key-1"}`))
+		case "/buckets/audit/object-lock":
+			w.WriteHeader(http.StatusNotFound)
+		case "/buckets/audit/tagging":
+			w.WriteHeader(http.StatusNotImplemented)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{Versioning: true, Encryption: true, ObjectLock: true, Tagging: true})
+	config, err := client.GetBucketConfig("audit")
+	require.NoError(t, err)
+	assert.Equal(t, "b1", config.Bucket.ID)
+	assert.Equal(t, "eu", config.Bucket.Region)
+	assert.Equal(t, "enabled", config.Versioning)
+	assert.Equal(t, &BucketEncryption{Algorithm: "aws:kms", KMSKeyID: "key-1"}, config.Encryption)
+	assert.Nil(t, config.ObjectLock)
+	assert.Empty(t, config.Tags)
+	assert.Equal(t, []string{"tagging"}, config.Unsupported)
+
+	_, err = client.GetBucketConfig("missing")
+	var objErr *Error
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, http.StatusNotFound, objErr.StatusCode)
+}
+
+func TestGetBucketConfigUnreported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/buckets" {
+			w.Write([]byte(`{"buckets":[{"id":"b1","name":"audit"}]}`))
+			return
+		}
+		// Like the real server, which routes no bucket subresources.
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	// Without capabilities, a 404 could mean either missing route or no
+	// configuration, so nothing is reported as unconfigured.
+	client := NewClient(server.URL)
+	config, err := client.GetBucketConfig("audit")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"encryption", "object-lock", "tagging", "versioning"}, config.Unsupported)
+
+	client.capabilities.Store(&ServerCapabilities{Versioning: true})
+	config, err = client.GetBucketConfig("audit")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"encryption", "object-lock", "tagging"}, config.Unsupported)
+	assert.Empty(t, config.Versioning)
+}
@@ -0,0 +1,88 @@
+package objectstorage
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ServerCapabilities reports which optional features the server implements.
+type ServerCapabilities struct {
+	Versioning bool `json:"versioning"`
+	Multipart  bool `json:"multipart"`
+	Tagging    bool `json:"tagging"`
+	Ranges     bool `json:"ranges"`
+	Copy       bool `json:"copy"`
+	Append     bool `json:"append"`
+	AtomicMove bool `json:"atomic_move"`
+	ObjectLock bool `json:"object_lock"`
+	ChangeFeed bool `json:"change_feed"`
+	ACL        bool `json:"acl"`
+	Encryption bool `json:"encryption"`
+}
+
+// Capabilities asks the server which optional features it implements, via
+// its discovery endpoint at /capabilities. It returns ErrNotSupported if the
+// server does not advertise its capabilities.
+//
+// The result is remembered: afterwards AppendObject, the multipart upload
+// calls and ListObjectVersions return ErrNotSupported without contacting the server if it lacks the
+// feature. Calls with a fallback take it directly: MoveObject copies and
+// deletes without AtomicMove, ListObjectsSince lists the bucket without
+// ChangeFeed, and without Ranges the range and download helpers request
+// whole objects.
+//
+// The ACL, tagging and legal hold calls work the other way round: they
+// return ErrNotSupported until Capabilities has reported ACL, Tagging or
+// ObjectLock support. GetBucketConfig likewise only reads the bucket
+// subresources Capabilities has reported.
+func (c *Client) Capabilities() (*ServerCapabilities, error) {
+	req, err := http.NewRequest("GET", c.baseURL+"/capabilities", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do("Capabilities", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return nil, ErrNotSupported
+	default:
+		return nil, c.newError(resp)
+	}
+
+	var caps ServerCapabilities
+	if err := json.NewDecoder(resp.Body).Decode(&caps); err != nil {
+		return nil, err
+	}
+	c.capabilities.Store(&caps)
+
+	copied := caps
+	return &copied, nil
+}
+
+// requireCapability returns ErrNotSupported if Capabilities has reported
+// that the server lacks the feature selected by has. Before Capabilities has
+// succeeded, every feature is assumed to be present.
+func (c *Client) requireCapability(has func(*ServerCapabilities) bool) error {
+	if caps := c.capabilities.Load(); caps != nil && !has(caps) {
+		return ErrNotSupported
+	}
+	return nil
+}
+
+// requireConfirmedCapability is requireCapability for features that live on
+// an object subresource such as ?acl. A server without the feature ignores
+// the query, so a PUT would overwrite the object with the request body and a
+// GET would return its content. Such features therefore count as absent
+// until Capabilities has reported them.
+func (c *Client) requireConfirmedCapability(has func(*ServerCapabilities) bool) error {
+	if caps := c.capabilities.Load(); caps == nil || !has(caps) {
+		return ErrNotSupported
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package objectstorage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilities(t *testing.T) {
+	var appends int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/capabilities":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"versioning":true,"Multipart":true,"atomicMove":true,"append":false}`))
+		default:
+			atomic.AddInt32(&appends, 1)
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	caps, err := client.Capabilities()
+	require.NoError(t, err)
+	assert.Equal(t, &ServerCapabilities{Versioning: true, Multipart: true, AtomicMove: true}, caps)
+
+	_, err = client.AppendObject("test-bucket", "log.txt", []byte("x"), 0)
+	assert.ErrorIs(t, err, ErrNotSupported)
+	_, err = client.GetObjectTags("test-bucket", "log.txt")
+	assert.ErrorIs(t, err, ErrNotSupported)
+	assert.Zero(t, atomic.LoadInt32(&appends))
+
+	// The returned value is a copy; changing it does not affect the client.
+	caps.Append = true
+	_, err = client.AppendObject("test-bucket", "log.txt", []byte("x"), 0)
+	assert.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestCapabilitiesNotAdvertised(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+
+		client := NewClient(server.URL)
+		_, err := client.Capabilities()
+		assert.ErrorIs(t, err, ErrNotSupported, "status %d", status)
+		assert.NoError(t, client.requireCapability(func(s *ServerCapabilities) bool { return s.Tagging }))
+		server.Close()
+	}
+}
+
+func TestCapabilitiesSkipMissingFeatures(t *testing.T) {
+	var requests []string
+	content := serveContent([]byte("Hello, World!"), `"abc123"`, nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("Range"))
+		if r.URL.Path == "/buckets/test-bucket/objects" {
+			w.Write([]byte(`{"objects":[]}`))
+			return
+		}
+		content.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{})
+
+	assert.ErrorIs(t, client.PutObjectLegalHold("test-bucket", "k", true), ErrNotSupported)
+	_, err := client.GetObjectLegalHold("test-bucket", "k")
+	assert.ErrorIs(t, err, ErrNotSupported)
+
+	// Without a change feed the bucket is listed directly.
+	_, err = client.ListObjectsSince("test-bucket", nil, time.Time{})
+	require.NoError(t, err)
+
+	// Without ranges, whole objects are requested and cut locally.
+	parts, err := client.GetObjectRanges("test-bucket", "k", [][2]int64{{7, 11}, {0, 4}})
+	require.NoError(t, err)
+	assert.Equal(t, []RangePart{{Start: 7, Data: []byte("World")}, {Start: 0, Data: []byte("Hello")}}, parts)
+	obj, err := client.GetObjectRange("test-bucket", "k", 7, 11)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("Hello, World!"), obj.Data)
+
+	assert.Equal(t, []string{
+		"GET /buckets/test-bucket/objects ",
+		"GET /buckets/test-bucket/objects/k ",
+		"GET /buckets/test-bucket/objects/k ",
+	}, requests)
+}
+
+func TestCapabilitiesSkipAtomicMove(t *testing.T) {
+	server := newMemObjectServer(t)
+	defer server.Close()
+	server.put("src-bucket/src-key", "hello", nil)
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{})
+	result, err := client.MoveObject("src-bucket", "src-key", "dst-bucket", "dst-key")
+	require.NoError(t, err)
+	assert.False(t, result.Atomic)
+	for _, req := range server.requests {
+		assert.NotContains(t, req, "POST")
+	}
+	assert.Equal(t, "hello", string(server.get("dst-bucket/dst-key").data))
+}
@@ -0,0 +1,74 @@
+package objectstorage
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+)
+
+// PutObjectContentAddressed uploads the content of r under the key prefix
+// followed by the hex-encoded SHA-256 of the content. The digest is not known
+// until r is exhausted, so the content is streamed to a temporary key under
+// prefix while it is hashed and then moved to its final key with MoveObject;
+// nothing is buffered in memory.
+//
+// If an object of the uploaded size already exists under the final key it
+// holds the same content, so the temporary object is deleted instead of
+// moved and the existing object's metadata is returned with Skipped set. An
+// object of a different size there is replaced.
+//
+// The moved object must have the uploaded size and ETag. If it does not, for
+// example because the move was emulated by a copy that lost the data, it is
+// deleted so that later uploads of the same content do not skip it, and an
+// error wrapping ErrCopyMismatch is returned. The temporary object is also
+// deleted, on a best-effort basis, if the move fails.
+func (c *Client) PutObjectContentAddressed(bucket string, r io.Reader, prefix string, contentType *string, metadata map[string]string) (string, *ObjectMetadata, error) {
+	var suffix [8]byte
+	if _, err := rand.Read(suffix[:]); err != nil {
+		return "", nil, err
+	}
+	tempKey := prefix + ".upload-" + hex.EncodeToString(suffix[:])
+
+	body := &countingReader{r: r, size: -1, hash: sha256.New()}
+	newBody := func() *countingReader { return body }
+	uploaded, err := c.putStream("PutObjectContentAddressed", bucket, tempKey, -1, contentType, metadata, newBody, false)
+	if err != nil {
+		return "", nil, err
+	}
+	n, digest := body.sum()
+	size := uint64(n)
+	key := prefix + hex.EncodeToString(digest)
+
+	existing, err := c.HeadObject(bucket, key)
+	if err == nil && existing.Size == size {
+		if err := c.DeleteObject(bucket, tempKey); err != nil {
+			return "", nil, err
+		}
+		existing.Skipped = true
+		return key, existing, nil
+	}
+	if err != nil && !isNotFound(err) {
+		c.DeleteObject(bucket, tempKey)
+		return "", nil, err
+	}
+
+	moved, err := c.MoveObject(bucket, tempKey, bucket, key)
+	if err != nil {
+		if errors.Is(err, ErrCopyMismatch) {
+			c.DeleteObject(bucket, key)
+		}
+		c.DeleteObject(bucket, tempKey)
+		return "", nil, err
+	}
+	if err := verifyCopy(size, uploaded.ETag, &moved.Object); err != nil {
+		var opts []RequestOption
+		if moved.Object.ETag != "" {
+			opts = append(opts, WithIfMatch(moved.Object.ETag))
+		}
+		c.DeleteObject(bucket, key, opts...)
+		return "", nil, err
+	}
+	return key, &moved.Object, nil
+}
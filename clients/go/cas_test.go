@@ -0,0 +1,148 @@
+package objectstorage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// casServer is an in-memory object store supporting PUT, HEAD, DELETE and
+// the atomic move endpoint for a single bucket.
+func casServer(t *testing.T, objects map[string][]byte) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := strings.TrimPrefix(r.URL.Path, "/buckets/cas/objects/")
+		switch {
+		case r.Method == "PUT":
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			objects[key] = data
+			json.NewEncoder(w).Encode(ObjectMetadata{Key: key, Size: uint64(len(data)), ETag: ComputeETag(data)})
+		case r.Method == "HEAD":
+			data, ok := objects[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("ETag", ComputeETag(data))
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		case r.Method == "DELETE":
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "POST" && r.URL.RawQuery == "move":
+			var req moveObjectRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			objects[req.Key] = objects[key]
+			delete(objects, key)
+			json.NewEncoder(w).Encode(ObjectMetadata{Key: req.Key, Size: uint64(len(objects[req.Key])), ETag: ComputeETag(objects[req.Key])})
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+}
+
+func TestPutObjectContentAddressed(t *testing.T) {
+	objects := map[string][]byte{}
+	server := casServer(t, objects)
+	defer server.Close()
+
+	content := strings.Repeat("blob", 1000)
+	sum := sha256.Sum256([]byte(content))
+	want := "sha256/" + hex.EncodeToString(sum[:])
+
+	client := NewClient(server.URL)
+	key, meta, err := client.PutObjectContentAddressed("cas", strings.NewReader(content), "sha256/", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, want, key)
+	assert.Equal(t, want, meta.Key)
+	assert.False(t, meta.Skipped)
+	assert.Equal(t, map[string][]byte{want: []byte(content)}, objects)
+}
+
+func TestPutObjectContentAddressedDedup(t *testing.T) {
+	content := "already stored"
+	sum := sha256.Sum256([]byte(content))
+	want := "sha256/" + hex.EncodeToString(sum[:])
+
+	objects := map[string][]byte{want: []byte(content)}
+	server := casServer(t, objects)
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	key, meta, err := client.PutObjectContentAddressed("cas", strings.NewReader(content), "sha256/", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, want, key)
+	assert.True(t, meta.Skipped)
+	assert.Equal(t, hex.EncodeToString(sum[:]), meta.ETag)
+	// The temporary object is gone and the existing one is untouched.
+	assert.Equal(t, map[string][]byte{want: []byte(content)}, objects)
+}
+
+func TestPutObjectContentAddressedWithoutMove(t *testing.T) {
+	// memObjectServer has no move endpoint and answers POST with 405.
+	server := newMemObjectServer(t)
+	defer server.Close()
+
+	content := strings.Repeat("blob", 1000)
+	want := "sha256/" + ComputeETag([]byte(content))
+
+	client := NewClient(server.URL)
+	key, meta, err := client.PutObjectContentAddressed("cas", strings.NewReader(content), "sha256/", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, want, key)
+	assert.Equal(t, uint64(len(content)), meta.Size)
+	require.Len(t, server.objects, 1)
+	assert.Equal(t, content, string(server.get("cas/"+want).data))
+}
+
+func TestPutObjectContentAddressedLostCopy(t *testing.T) {
+	// The client believes the server copies, but the server ignores the copy
+	// source and stores an empty object.
+	server := newMemObjectServer(t)
+	defer server.Close()
+
+	content := "precious"
+	want := "sha256/" + ComputeETag([]byte(content))
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{Copy: true})
+	_, _, err := client.PutObjectContentAddressed("cas", strings.NewReader(content), "sha256/", nil, nil)
+	assert.ErrorIs(t, err, ErrCopyMismatch)
+	// Nothing is left under the final key for a later upload to skip.
+	assert.Nil(t, server.get("cas/"+want))
+
+	client.capabilities.Store(&ServerCapabilities{})
+	key, meta, err := client.PutObjectContentAddressed("cas", strings.NewReader(content), "sha256/", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, want, key)
+	assert.False(t, meta.Skipped)
+	assert.Equal(t, content, string(server.get("cas/"+want).data))
+}
+
+func TestPutObjectContentAddressedReplacesWrongSize(t *testing.T) {
+	content := "full content"
+	sum := sha256.Sum256([]byte(content))
+	want := "sha256/" + hex.EncodeToString(sum[:])
+
+	objects := map[string][]byte{want: nil}
+	server := casServer(t, objects)
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, meta, err := client.PutObjectContentAddressed("cas", strings.NewReader(content), "sha256/", nil, nil)
+	require.NoError(t, err)
+	assert.False(t, meta.Skipped)
+	assert.Equal(t, map[string][]byte{want: []byte(content)}, objects)
+}
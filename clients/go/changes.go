@@ -0,0 +1,107 @@
+package objectstorage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// LastModifiedTime parses LastModified, returning the zero time if it is
+// empty or malformed.
+func (m *ObjectMetadata) LastModifiedTime() time.Time {
+	t, _ := parseLastModified(m.LastModified)
+	return t
+}
+
+// ListObjectsSince returns the objects under prefix modified after since,
+// oldest first. The newest LastModifiedTime among them can be persisted and
+// passed as since on the next poll to fetch only later changes.
+//
+// It reads the server's change feed at /buckets/{bucket}/changes when
+// available. Otherwise it falls back to listing every object under prefix
+// and filtering by modification time, which costs a full listing per call.
+func (c *Client) ListObjectsSince(bucket string, prefix *string, since time.Time) ([]ObjectMetadata, error) {
+	objects, err := c.listChanges(bucket, prefix, since)
+	if err == ErrNotSupported {
+		err = c.ForEachObject(context.Background(), bucket, prefix, func(obj ObjectMetadata) error {
+			objects = append(objects, obj)
+			return nil
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	changed := objects[:0]
+	for _, obj := range objects {
+		if obj.LastModifiedTime().After(since) {
+			changed = append(changed, obj)
+		}
+	}
+	sort.SliceStable(changed, func(i, j int) bool {
+		return changed[i].LastModifiedTime().Before(changed[j].LastModifiedTime())
+	})
+	return changed, nil
+}
+
+// listChanges pages through the change feed, returning ErrNotSupported if
+// the server has none.
+func (c *Client) listChanges(bucket string, prefix *string, since time.Time) ([]ObjectMetadata, error) {
+	if err := c.requireCapability(func(s *ServerCapabilities) bool { return s.ChangeFeed }); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	params.Set("since", since.UTC().Format(time.RFC3339Nano))
+	if prefix != nil {
+		params.Set("prefix", *prefix)
+	}
+	if c.keyEncoding == KeyEncodingURL {
+		params.Set("encoding-type", "url")
+	}
+
+	var objects []ObjectMetadata
+	for {
+		req, err := http.NewRequest("GET", c.bucketURL(bucket)+"/changes?"+params.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := c.do("ListObjectsSince", req)
+		if err != nil {
+			return nil, err
+		}
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+		case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			// A missing bucket is reported by the fallback listing.
+			drainBody(resp)
+			return nil, ErrNotSupported
+		default:
+			defer resp.Body.Close()
+			return nil, c.newError(resp)
+		}
+
+		var page listObjectsResponse
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, obj := range page.Objects {
+			if obj.Key, err = c.keyEncoding.decode(obj.Key); err != nil {
+				return nil, err
+			}
+			objects = append(objects, obj)
+		}
+		if page.NextContinuationToken == "" {
+			return objects, nil
+		}
+		params.Set("continuation_token", page.NextContinuationToken)
+	}
+}
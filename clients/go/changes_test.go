@@ -0,0 +1,60 @@
+package objectstorage
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListObjectsSinceChangeFeed(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/buckets/test-bucket/changes", r.URL.Path)
+		assert.Equal(t, "2024-01-01T12:00:00Z", r.URL.Query().Get("since"))
+		assert.Equal(t, "logs/", r.URL.Query().Get("prefix"))
+		if r.URL.Query().Get("continuation_token") == "" {
+			fmt.Fprint(w, `{"objects":[{"key":"logs/b","last_modified":"2024-01-01T14:00:00Z"}],"next_continuation_token":"p2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"objects":[{"key":"logs/a","last_modified":"2024-01-01T13:00:00Z"}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	prefix := "logs/"
+	objects, err := client.ListObjectsSince("test-bucket", &prefix, since)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "logs/a", objects[0].Key)
+	assert.Equal(t, "logs/b", objects[1].Key)
+	assert.Equal(t, time.Date(2024, 1, 1, 14, 0, 0, 0, time.UTC), objects[1].LastModifiedTime())
+}
+
+func TestListObjectsSinceFallback(t *testing.T) {
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/buckets/test-bucket/changes" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Equal(t, "/buckets/test-bucket/objects", r.URL.Path)
+		fmt.Fprint(w, `{"objects":[
+			{"key":"c","last_modified":"2024-01-02T00:00:00Z"},
+			{"key":"old","last_modified":"2024-01-01T11:00:00Z"},
+			{"key":"same","last_modified":"2024-01-01T12:00:00Z"},
+			{"key":"b","last_modified":"2024-01-01T12:00:01Z"}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	objects, err := client.ListObjectsSince("test-bucket", nil, since)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "b", objects[0].Key)
+	assert.Equal(t, "c", objects[1].Key)
+}
@@ -0,0 +1,40 @@
+package objectstorage
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+// ErrChecksumMismatch is returned when data does not match the checksum
+// recorded for it.
+var ErrChecksumMismatch = errors.New("objectstorage: checksum mismatch")
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ComputeCRC32C returns the CRC32C (Castagnoli) checksum of data in the form
+// used by the x-object-checksum-crc32c header: the big-endian checksum bytes,
+// base64-encoded.
+func ComputeCRC32C(data []byte) string {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], crc32.Checksum(data, castagnoliTable))
+	return base64.StdEncoding.EncodeToString(buf[:])
+}
+
+// WithCRC32C computes the CRC32C checksum of the uploaded data and sends it
+// so the server can verify the upload.
+func WithCRC32C() RequestOption {
+	return func(o *requestOptions) {
+		o.sendCRC32C = true
+	}
+}
+
+// WithChecksumVerification makes GetObject verify the downloaded data against
+// the CRC32C checksum reported by the server, failing with
+// ErrChecksumMismatch on mismatch. Objects without a checksum are not checked.
+func WithChecksumVerification() RequestOption {
+	return func(o *requestOptions) {
+		o.verifyCRC32C = true
+	}
+}
@@ -0,0 +1,51 @@
+package objectstorage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComputeCRC32C(t *testing.T) {
+	// Known CRC32C check value for "123456789" is 0xE3069283.
+	assert.Equal(t, "4waSgw==", ComputeCRC32C([]byte("123456789")))
+}
+
+func TestPutObjectWithCRC32C(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, ComputeCRC32C([]byte("Hello, World!")), r.Header.Get("x-object-checksum-crc32c"))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"key":"test-key"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.PutObject("test-bucket", "test-key", []byte("Hello, World!"), nil, nil, WithCRC32C())
+	require.NoError(t, err)
+}
+
+func TestGetObjectChecksumVerification(t *testing.T) {
+	checksum := ComputeCRC32C([]byte("Hello, World!"))
+	body := "Hello, World!"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-object-checksum-crc32c", checksum)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.GetObject("test-bucket", "test-key", WithChecksumVerification())
+	require.NoError(t, err)
+	assert.Equal(t, checksum, obj.Metadata.CRC32C)
+
+	body = "Hello, World?"
+	_, err = client.GetObject("test-bucket", "test-key", WithChecksumVerification())
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+
+	_, err = client.GetObject("test-bucket", "test-key")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,85 @@
+package objectstorage
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// circuitBreaker stops sending requests after too many consecutive server
+// or transport failures. Once open, it rejects requests until the cooldown
+// has passed and then lets a single probe through: if the probe succeeds the
+// circuit closes, otherwise it opens again for another cooldown.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	open     bool
+	openedAt time.Time
+	probing  bool
+}
+
+// WithCircuitBreaker makes the client fail fast with ErrCircuitOpen after
+// failureThreshold consecutive failed requests, for cooldown. After that a
+// single request is let through to test whether the server has recovered.
+// Only transport errors and 5xx responses count as failures; other 4xx
+// responses mean the server is working and reset the count.
+func WithCircuitBreaker(failureThreshold int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		if failureThreshold < 1 {
+			failureThreshold = 1
+		}
+		c.breaker = &circuitBreaker{threshold: failureThreshold, cooldown: cooldown}
+	}
+}
+
+// allow reports ErrCircuitOpen if a request may not be sent at now.
+func (b *circuitBreaker) allow(now time.Time) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.open {
+		return nil
+	}
+	if b.probing || now.Sub(b.openedAt) < b.cooldown {
+		return ErrCircuitOpen
+	}
+	b.probing = true
+	return nil
+}
+
+// record updates the breaker with the outcome of a request let through by
+// allow, completed at now.
+func (b *circuitBreaker) record(resp *http.Response, err error, now time.Time) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	wasProbe := b.probing
+	b.probing = false
+
+	switch {
+	case errors.Is(err, ErrTransport) || (err == nil && resp.StatusCode >= 500):
+		b.failures++
+		if wasProbe || b.failures >= b.threshold {
+			b.open = true
+			b.openedAt = now
+		}
+	case err == nil:
+		b.failures = 0
+		b.open = false
+	}
+	// Other errors, such as a cancelled context, say nothing about the
+	// server. A cancelled probe leaves the circuit open but lets the next
+	// request probe again.
+}
@@ -0,0 +1,82 @@
+package objectstorage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var status atomic.Int32
+	var hits atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithCircuitBreaker(2, 50*time.Millisecond))
+	for i := 0; i < 2; i++ {
+		err := client.Ping()
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+
+	assert.ErrorIs(t, client.Ping(), ErrCircuitOpen)
+	assert.Equal(t, int32(2), hits.Load())
+
+	// A failed probe after the cooldown opens the circuit again.
+	time.Sleep(60 * time.Millisecond)
+	assert.NotErrorIs(t, client.Ping(), ErrCircuitOpen)
+	assert.ErrorIs(t, client.Ping(), ErrCircuitOpen)
+	assert.Equal(t, int32(3), hits.Load())
+
+	// A successful probe closes it.
+	status.Store(http.StatusOK)
+	time.Sleep(60 * time.Millisecond)
+	require.NoError(t, client.Ping())
+	require.NoError(t, client.Ping())
+	assert.Equal(t, int32(5), hits.Load())
+}
+
+func TestCircuitBreakerIgnoresClientErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithCircuitBreaker(1, time.Hour))
+	for i := 0; i < 3; i++ {
+		err := client.DeleteObject("test-bucket", "test-key")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+}
+
+func TestCircuitBreakerTransportErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	var attempts int
+	client := NewClient(url,
+		WithCircuitBreaker(2, time.Hour),
+		WithRetry(RetryPolicy{
+			MaxAttempts:      5,
+			InitialBackoff:   time.Millisecond,
+			ShouldRetryError: func(error) bool { return true },
+		}),
+		WithFaultInjector(func(op string, attempt int) error {
+			attempts++
+			return nil
+		}),
+	)
+	assert.ErrorIs(t, client.Ping(), ErrCircuitOpen)
+	assert.Equal(t, 2, attempts)
+}
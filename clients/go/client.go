@@ -2,33 +2,95 @@ package objectstorage
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+
+	faultInjector  func(op string, attempt int) error
+	retry          *RetryPolicy
+	throttle       bool
+	rateLimit      rateLimitState
+	breaker        *circuitBreaker
+	clock          Clock
+	connTrace      *connTracer
+	stats          *statsCollector
+	readAfterWrite *writeTracker
+	coalescer      *coalescer
+	dnsCache       *dnsCache
+	capabilities   atomic.Pointer[ServerCapabilities]
+
+	expectContinueThreshold int64
+	keyEncoding             KeyEncoding
+	virtualHosted           bool
+	decompress              bool
+	metadataLimit           int
+
+	ownsHTTPClient bool
+	ownsTransport  bool
+
+	// optionErr is the first error reported by an Option. It fails every
+	// request, since NewClient cannot return it.
+	optionErr error
+
+	// moveSupport caches whether the server has an atomic move endpoint.
+	moveSupport int32
+	closed      atomic.Bool
 }
 
 type Bucket struct {
 	ID        string `json:"id"`
 	Name      string `json:"name"`
 	CreatedAt string `json:"created_at"`
+	// Region is the region the bucket lives in, if the server reports one.
+	Region string `json:"region,omitempty"`
 }
 
 type ObjectMetadata struct {
-	Key          string            `json:"key"`
-	Size         uint64            `json:"size"`
-	ContentType  *string           `json:"content_type,omitempty"`
-	ETag         string            `json:"etag"`
-	LastModified string            `json:"last_modified"`
-	Metadata     map[string]string `json:"metadata"`
+	Key             string  `json:"key"`
+	Size            uint64  `json:"size"`
+	ContentType     *string `json:"content_type,omitempty"`
+	ContentLanguage string  `json:"content_language,omitempty"`
+	// ETag is the entity tag without quotes or W/ prefix, however the server
+	// sent it. Conditional options such as WithIfMatch add the quotes back.
+	ETag               string    `json:"etag"`
+	LastModified       string    `json:"last_modified"`
+	StorageClass       string    `json:"storage_class,omitempty"`
+	CRC32C             string    `json:"checksum_crc32c,omitempty"`
+	ACL                CannedACL `json:"acl,omitempty"`
+	ContentEncoding    string    `json:"content_encoding,omitempty"`
+	ContentDisposition string    `json:"content_disposition,omitempty"`
+	CacheControl       string    `json:"cache_control,omitempty"`
+	LegalHold          bool      `json:"legal_hold,omitempty"`
+	// WebsiteRedirectLocation is the target the server redirects to with a
+	// 301 when the object is requested through the website endpoint.
+	WebsiteRedirectLocation string            `json:"website_redirect_location,omitempty"`
+	Metadata                map[string]string `json:"metadata"`
+	// SystemMetadata holds every other X-* response header, such as
+	// X-Object-Created-By or replication status headers, keyed by canonical
+	// header name. It is only set for metadata read from response headers.
+	SystemMetadata map[string]string `json:"system_metadata,omitempty"`
+	// RequestID is the server-assigned X-Request-Id of the response this
+	// metadata came from, for support correlation. It is empty for objects
+	// returned by listings.
+	RequestID string `json:"-"`
+	// Skipped reports that an upload was not stored: PutObject with
+	// WithExpectedETag found identical content already stored,
+	// PutObjectIfLarger found an object at least as large, or
+	// PutObjectContentAddressed found the content already stored.
+	Skipped bool `json:"-"`
 }
 
 type ObjectData struct {
@@ -37,7 +99,13 @@ type ObjectData struct {
 }
 
 type createBucketRequest struct {
-	Name string `json:"name"`
+	Name   string `json:"name"`
+	Region string `json:"region,omitempty"`
+}
+
+type updateObjectMetadataRequest struct {
+	ContentType *string           `json:"content_type,omitempty"`
+	Metadata    map[string]string `json:"metadata"`
 }
 
 type listBucketsResponse struct {
@@ -45,7 +113,10 @@ type listBucketsResponse struct {
 }
 
 type listObjectsResponse struct {
-	Objects []ObjectMetadata `json:"objects"`
+	Objects               []ObjectMetadata `json:"objects"`
+	CommonPrefixes        []string         `json:"common_prefixes,omitempty"`
+	IsTruncated           bool             `json:"is_truncated,omitempty"`
+	NextContinuationToken string           `json:"next_continuation_token,omitempty"`
 }
 
 type PublicUrlPurpose string
@@ -60,49 +131,154 @@ type PublicURLResponse struct {
 	ExpiresIn uint64 `json:"expires_in"`
 }
 
-type Error struct {
-	StatusCode int
-	Message    string
-}
-
-func (e *Error) Error() string {
-	return fmt.Sprintf("object storage error (status %d): %s", e.StatusCode, e.Message)
-}
-
-func NewClient(baseURL string) *Client {
-	return &Client{
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
 		baseURL: baseURL,
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		ownsHTTPClient: true,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-func NewClientWithHTTP(baseURL string, httpClient *http.Client) *Client {
-	return &Client{
+func NewClientWithHTTP(baseURL string, httpClient *http.Client, opts ...Option) *Client {
+	c := &Client{
 		baseURL:    baseURL,
 		httpClient: httpClient,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Close releases the client's idle connections. Afterwards every call fails
+// with ErrClientClosed. Closing an already closed client is a no-op.
+//
+// Close acts on the underlying http.Client, so a client created with
+// NewClientWithHTTP around an http.Client shared with other code should not
+// be closed; those connections belong to the caller.
+func (c *Client) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	c.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// do sends req on behalf of the named operation. All requests go through here
+// so that client-wide behaviour (fault injection, rate limiting, retries,
+// read-after-write retries and statistics) applies uniformly.
+func (c *Client) do(op string, req *http.Request) (resp *http.Response, err error) {
+	if c.closed.Load() {
+		return nil, ErrClientClosed
+	}
+	if c.optionErr != nil {
+		return nil, c.optionErr
+	}
+
+	if c.stats != nil {
+		start := c.now()
+		defer func() {
+			c.stats.record(op, c.now().Sub(start), resp, err)
+		}()
+	}
+
+	resp, err = c.doRetry(op, req)
+	if c.readAfterWrite == nil || err != nil {
+		return resp, err
+	}
+	return c.readAfterWrite.handle(c.timeSource(), req, resp, func() (*http.Response, error) {
+		return c.doRetry(op, req)
+	})
+}
+
+// doRetry sends req, retrying according to the client's RetryPolicy.
+func (c *Client) doRetry(op string, req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	maxAttempts := c.retry.attempts()
+
+	for attempt := 1; ; attempt++ {
+		if err := c.waitForRateLimit(ctx); err != nil {
+			return nil, err
+		}
+
+		if err := c.breaker.allow(c.now()); err != nil {
+			return nil, err
+		}
+		resp, err := c.send(op, req, attempt)
+		c.breaker.record(resp, err, c.now())
+		if attempt >= maxAttempts {
+			return resp, err
+		}
+
+		var retryAfter time.Duration
+		if err != nil {
+			if !c.retry.shouldRetryError(err) {
+				return nil, err
+			}
+		} else {
+			if !isRetryableStatus(resp.StatusCode) {
+				return resp, nil
+			}
+			retryAfter, _ = parseRetryAfter(resp.Header, c.now())
+		}
+		delay, ok := c.retry.backoff(attempt, retryAfter)
+		if !ok || !rewindBody(req) {
+			return resp, err
+		}
+		if resp != nil {
+			drainBody(resp)
+		}
+
+		if err := c.sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *Client) send(op string, req *http.Request, attempt int) (*http.Response, error) {
+	if c.faultInjector != nil {
+		if err := c.faultInjector(op, attempt); err != nil {
+			return nil, newTransportError(req, err)
+		}
+	}
+
+	if c.connTrace != nil {
+		req = c.connTrace.trace(req)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, newTransportError(req, err)
+	}
+	c.observeRateLimit(resp)
+	return resp, nil
 }
 
 func (c *Client) Ping() error {
-	req, err := http.NewRequest("GET", c.baseURL+"/ping", nil)
+	return c.PingContext(context.Background())
+}
+
+// PingContext is Ping bounded by ctx, independently of the client's timeout.
+func (c *Client) PingContext(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/ping", nil)
 	if err != nil {
 		return err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("Ping", req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return &Error{
-			StatusCode: resp.StatusCode,
-			Message:    string(bodyBytes),
-		}
+		return c.newError(resp)
 	}
 
 	return nil
@@ -121,18 +297,14 @@ func (c *Client) CreateBucket(name string) (*Bucket, error) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("CreateBucket", req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, &Error{
-			StatusCode: resp.StatusCode,
-			Message:    string(bodyBytes),
-		}
+		return nil, c.newError(resp)
 	}
 
 	var bucket Bucket
@@ -156,18 +328,14 @@ func (c *Client) UpsertBucket(name string) (*Bucket, error) {
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("UpsertBucket", req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, &Error{
-			StatusCode: resp.StatusCode,
-			Message:    string(bodyBytes),
-		}
+		return nil, c.newError(resp)
 	}
 
 	var bucket Bucket
@@ -184,18 +352,14 @@ func (c *Client) GetBucket(id string) (*Bucket, error) {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("GetBucket", req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, &Error{
-			StatusCode: resp.StatusCode,
-			Message:    string(bodyBytes),
-		}
+		return nil, c.newError(resp)
 	}
 
 	var bucket Bucket
@@ -207,23 +371,30 @@ func (c *Client) GetBucket(id string) (*Bucket, error) {
 }
 
 func (c *Client) ListBuckets() ([]Bucket, error) {
-	req, err := http.NewRequest("GET", c.baseURL+"/buckets", nil)
+	return c.ListBucketsWithPrefix("")
+}
+
+// ListBucketsWithPrefix lists the buckets whose names start with prefix. The
+// prefix is sent to the server for filtering and also applied to the result,
+// so it works with servers that ignore it.
+func (c *Client) ListBucketsWithPrefix(prefix string) ([]Bucket, error) {
+	urlPath := c.baseURL + "/buckets"
+	if prefix != "" {
+		urlPath += "?" + url.Values{"prefix": {prefix}}.Encode()
+	}
+	req, err := http.NewRequest("GET", urlPath, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("ListBuckets", req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, &Error{
-			StatusCode: resp.StatusCode,
-			Message:    string(bodyBytes),
-		}
+		return nil, c.newError(resp)
 	}
 
 	var result listBucketsResponse
@@ -231,7 +402,16 @@ func (c *Client) ListBuckets() ([]Bucket, error) {
 		return nil, err
 	}
 
-	return result.Buckets, nil
+	if prefix == "" {
+		return result.Buckets, nil
+	}
+	buckets := make([]Bucket, 0, len(result.Buckets))
+	for _, b := range result.Buckets {
+		if strings.HasPrefix(b.Name, prefix) {
+			buckets = append(buckets, b)
+		}
+	}
+	return buckets, nil
 }
 
 func (c *Client) DeleteBucket(name string) error {
@@ -240,26 +420,25 @@ func (c *Client) DeleteBucket(name string) error {
 		return err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("DeleteBucket", req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return &Error{
-			StatusCode: resp.StatusCode,
-			Message:    string(bodyBytes),
-		}
+		return c.newError(resp)
 	}
 
 	return nil
 }
 
-func (c *Client) PutObject(bucket, key string, data []byte, contentType *string, metadata map[string]string) (*ObjectMetadata, error) {
-	urlPath := fmt.Sprintf("%s/buckets/%s/objects/%s", c.baseURL, bucket, key)
-	req, err := http.NewRequest("PUT", urlPath, bytes.NewReader(data))
+func (c *Client) PutObject(bucket, key string, data []byte, contentType *string, metadata map[string]string, opts ...RequestOption) (*ObjectMetadata, error) {
+	if err := c.validateMetadata(metadata); err != nil {
+		return nil, err
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "PUT", "objects", bucket, key, "", bytes.NewReader(data))
 	if err != nil {
 		return nil, err
 	}
@@ -268,237 +447,395 @@ func (c *Client) PutObject(bucket, key string, data []byte, contentType *string,
 		req.Header.Set("Content-Type", *contentType)
 	}
 
-	for k, v := range metadata {
-		req.Header.Set("x-object-meta-"+k, v)
+	setMetadataHeaders(req.Header, metadata)
+
+	options := newRequestOptions(opts)
+	options.apply(req)
+	if options.sendCRC32C {
+		req.Header.Set("x-object-checksum-crc32c", ComputeCRC32C(data))
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("PutObject", req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && options.expectedETag != "" {
+		return skippedPut(key, data, options.expectedETag, resp), nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, &Error{
-			StatusCode: resp.StatusCode,
-			Message:    string(bodyBytes),
-		}
+		return nil, c.newError(resp)
 	}
 
 	var objMetadata ObjectMetadata
 	if err := json.NewDecoder(resp.Body).Decode(&objMetadata); err != nil {
 		return nil, err
 	}
+	objMetadata.RequestID = resp.Header.Get("X-Request-Id")
 
 	return &objMetadata, nil
 }
 
-func (c *Client) GetObject(bucket, key string) (*ObjectData, error) {
-	urlPath := fmt.Sprintf("%s/buckets/%s/objects/%s", c.baseURL, bucket, key)
-	req, err := http.NewRequest("GET", urlPath, nil)
+// skippedPut describes an upload the server declined because it already
+// stores content with the expected ETag.
+func skippedPut(key string, data []byte, expectedETag string, resp *http.Response) *ObjectMetadata {
+	obj := metadataFromHeaders(key, resp.Header)
+	// A 304 carries no body, so its Content-Length is not the object size.
+	obj.Size = uint64(len(data))
+	if obj.ETag == "" {
+		obj.ETag = expectedETag
+	}
+	obj.Skipped = true
+	return &obj
+}
+
+// PutObjectCT is PutObject with a plain content type; an empty contentType
+// leaves it unset.
+func (c *Client) PutObjectCT(bucket, key string, data []byte, contentType string, metadata map[string]string, opts ...RequestOption) (*ObjectMetadata, error) {
+	return c.PutObject(bucket, key, data, optionalString(contentType), metadata, opts...)
+}
+
+func (c *Client) GetObject(bucket, key string, opts ...RequestOption) (*ObjectData, error) {
+	options := newRequestOptions(opts)
+	if c.coalescer != nil && !options.noCoalesce {
+		return c.coalescer.do(coalesceKey(bucket, key, options), func() (*ObjectData, error) {
+			return c.getObject(bucket, key, options)
+		})
+	}
+	return c.getObject(bucket, key, options)
+}
+
+func (c *Client) getObject(bucket, key string, options *requestOptions) (*ObjectData, error) {
+	req, err := c.newObjectRequest(context.Background(), "GET", "objects", bucket, key, "", nil)
 	if err != nil {
 		return nil, err
 	}
+	options.apply(req)
+	c.acceptStoredEncoding(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("GetObject", req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, &Error{
-			StatusCode: resp.StatusCode,
-			Message:    string(bodyBytes),
-		}
+		return nil, c.newError(resp)
 	}
 
 	data, err := io.ReadAll(resp.Body)
+	if err == nil && resp.ContentLength >= 0 && int64(len(data)) < resp.ContentLength {
+		err = io.ErrUnexpectedEOF
+	}
 	if err != nil {
-		return nil, err
+		data, err = c.resumeTruncated(bucket, key, resp, data, err)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	size, _ := strconv.ParseUint(resp.Header.Get("Content-Length"), 10, 64)
-	contentType := resp.Header.Get("Content-Type")
-	var ct *string
-	if contentType != "" {
-		ct = &contentType
+	metadata := metadataFromHeaders(key, resp.Header)
+	if options.verifyCRC32C && metadata.CRC32C != "" && ComputeCRC32C(data) != metadata.CRC32C {
+		return nil, ErrChecksumMismatch
 	}
 
-	// Extract custom metadata from x-object-meta-* headers
-	metadata := make(map[string]string)
-	for headerName, headerValues := range resp.Header {
-		if len(headerValues) > 0 {
-			const prefix = "X-Object-Meta-"
-			if len(headerName) > len(prefix) && headerName[:len(prefix)] == prefix {
-				metaKey := headerName[len(prefix):]
-				metadata[metaKey] = headerValues[0]
-			}
+	if c.decompress && metadata.ContentEncoding != "" {
+		decoded, ok, err := decodeContent(metadata.ContentEncoding, data)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			data = decoded
+			metadata.ContentEncoding = ""
 		}
 	}
+	if c.decompress || resp.ContentLength < 0 {
+		metadata.Size = uint64(len(data))
+	}
 
 	return &ObjectData{
-		Metadata: ObjectMetadata{
-			Key:          key,
-			Size:         size,
-			ContentType:  ct,
-			ETag:         resp.Header.Get("ETag"),
-			LastModified: resp.Header.Get("Last-Modified"),
-			Metadata:     metadata,
-		},
-		Data: data,
+		Metadata: metadata,
+		Data:     data,
 	}, nil
 }
 
-func (c *Client) HeadObject(bucket, key string) (*ObjectMetadata, error) {
-	urlPath := fmt.Sprintf("%s/buckets/%s/objects/%s", c.baseURL, bucket, key)
-	req, err := http.NewRequest("HEAD", urlPath, nil)
+// HeadObject returns an object's metadata without its content. With
+// WithIfNoneMatch or WithIfModifiedSince it revalidates cached metadata,
+// failing with ErrNotModified if the object is unchanged.
+func (c *Client) HeadObject(bucket, key string, opts ...RequestOption) (*ObjectMetadata, error) {
+	return c.headObject(context.Background(), "HeadObject", bucket, key, opts...)
+}
+
+// ObjectExists reports whether an object exists, using a HEAD request. Errors
+// other than 404 Not Found are returned.
+func (c *Client) ObjectExists(bucket, key string) (bool, error) {
+	return c.objectExists(context.Background(), "ObjectExists", bucket, key)
+}
+
+func (c *Client) objectExists(ctx context.Context, op, bucket, key string) (bool, error) {
+	_, err := c.headObject(ctx, op, bucket, key)
+	if err == nil {
+		return true, nil
+	}
+	if isNotFound(err) {
+		return false, nil
+	}
+	return false, err
+}
+
+func (c *Client) headObject(ctx context.Context, op, bucket, key string, opts ...RequestOption) (*ObjectMetadata, error) {
+	req, err := c.newObjectRequest(ctx, "HEAD", "objects", bucket, key, "", nil)
 	if err != nil {
 		return nil, err
 	}
+	newRequestOptions(opts).apply(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(op, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, &Error{
-			StatusCode: resp.StatusCode,
-			Message:    "Object not found",
-		}
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, c.errorFromResponse(resp, "Object not found")
+	default:
+		// HEAD responses have no body to take the message from.
+		return nil, c.errorFromResponse(resp, http.StatusText(resp.StatusCode))
+	}
+
+	metadata := metadataFromHeaders(key, resp.Header)
+	return &metadata, nil
+}
+
+// setMetadataHeaders sets an x-object-meta-* header for every metadata entry.
+// Keys are applied in sorted order so the resulting header set is identical
+// on every run, even when two keys canonicalize to the same header name. This
+// keeps canonical-request signatures computed by proxies reproducible.
+func setMetadataHeaders(h http.Header, metadata map[string]string) {
+	keys := make([]string, 0, len(metadata))
+	for k := range metadata {
+		keys = append(keys, k)
 	}
+	sort.Strings(keys)
 
-	size, _ := strconv.ParseUint(resp.Header.Get("Content-Length"), 10, 64)
-	contentType := resp.Header.Get("Content-Type")
+	for _, k := range keys {
+		h.Set("x-object-meta-"+k, metadata[k])
+	}
+}
+
+// optionalString returns nil for an empty string and a pointer to s otherwise.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// parseLastModified parses a last-modified value, which the server sends as
+// RFC 3339 in JSON bodies and may send as an HTTP date in headers.
+func parseLastModified(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, true
+	}
+	if t, err := http.ParseTime(s); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// metadataFromHeaders builds object metadata from the headers of a GET or
+// HEAD object response.
+func metadataFromHeaders(key string, h http.Header) ObjectMetadata {
+	size, _ := strconv.ParseUint(h.Get("Content-Length"), 10, 64)
+	contentType := h.Get("Content-Type")
 	var ct *string
 	if contentType != "" {
 		ct = &contentType
 	}
 
-	// Extract custom metadata from x-object-meta-* headers
+	// Extract custom metadata from x-object-meta-* headers, and keep other
+	// x-* headers as system metadata
 	metadata := make(map[string]string)
-	for headerName, headerValues := range resp.Header {
+	var system map[string]string
+	for headerName, headerValues := range h {
 		if len(headerValues) > 0 {
 			const prefix = "X-Object-Meta-"
 			if len(headerName) > len(prefix) && headerName[:len(prefix)] == prefix {
 				metaKey := headerName[len(prefix):]
 				metadata[metaKey] = headerValues[0]
+			} else if strings.HasPrefix(headerName, "X-") {
+				if system == nil {
+					system = make(map[string]string)
+				}
+				system[headerName] = headerValues[0]
 			}
 		}
 	}
 
-	return &ObjectMetadata{
-		Key:          key,
-		Size:         size,
-		ContentType:  ct,
-		ETag:         resp.Header.Get("ETag"),
-		LastModified: resp.Header.Get("Last-Modified"),
-		Metadata:     metadata,
-	}, nil
+	return ObjectMetadata{
+		Key:                     key,
+		Size:                    size,
+		ContentType:             ct,
+		ContentLanguage:         h.Get("Content-Language"),
+		ETag:                    opaqueETag(h.Get("ETag")),
+		LastModified:            h.Get("Last-Modified"),
+		StorageClass:            h.Get("X-Object-Storage-Class"),
+		CRC32C:                  h.Get("X-Object-Checksum-Crc32c"),
+		ACL:                     CannedACL(h.Get("X-Object-Acl")),
+		ContentEncoding:         h.Get("Content-Encoding"),
+		ContentDisposition:      h.Get("Content-Disposition"),
+		CacheControl:            h.Get("Cache-Control"),
+		LegalHold:               h.Get("X-Object-Legal-Hold") == legalHoldOn,
+		WebsiteRedirectLocation: h.Get("X-Object-Website-Redirect-Location"),
+		Metadata:                metadata,
+		SystemMetadata:          system,
+		RequestID:               h.Get("X-Request-Id"),
+	}
 }
 
-func (c *Client) GetObjectInfo(bucket, key string) (*ObjectMetadata, error) {
-	urlPath := fmt.Sprintf("%s/buckets/%s/object-info/%s", c.baseURL, bucket, key)
-	req, err := http.NewRequest("GET", urlPath, nil)
+// GetObjectInfo fetches an object's metadata from the object-info endpoint.
+// Like HeadObject, it accepts WithIfNoneMatch and WithIfModifiedSince to poll
+// for changes, failing with ErrNotModified without a body if the object is
+// unchanged.
+func (c *Client) GetObjectInfo(bucket, key string, opts ...RequestOption) (*ObjectMetadata, error) {
+	_, obj, err := c.getObjectInfo("GetObjectInfo", bucket, key, opts...)
+	return obj, err
+}
+
+// GetObjectInfoRaw is GetObjectInfo that also returns the response body as
+// sent by the server, so that fields ObjectMetadata does not model yet can be
+// read. The raw form is not covered by any compatibility promise: fields may
+// appear, change or disappear with server versions.
+func (c *Client) GetObjectInfoRaw(bucket, key string) (json.RawMessage, *ObjectMetadata, error) {
+	return c.getObjectInfo("GetObjectInfoRaw", bucket, key)
+}
+
+func (c *Client) getObjectInfo(op, bucket, key string, opts ...RequestOption) (json.RawMessage, *ObjectMetadata, error) {
+	req, err := c.newObjectRequest(context.Background(), "GET", "object-info", bucket, key, "", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	newRequestOptions(opts).apply(req)
+
+	resp, err := c.do(op, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, c.newError(resp)
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var objMetadata ObjectMetadata
+	if err := json.Unmarshal(raw, &objMetadata); err != nil {
+		return nil, nil, err
+	}
+	objMetadata.RequestID = resp.Header.Get("X-Request-Id")
+
+	return raw, &objMetadata, nil
+}
+
+// UpdateObjectMetadata replaces the content type and custom metadata of an
+// existing object without re-uploading its data. The returned metadata carries
+// the refreshed last-modified timestamp.
+func (c *Client) UpdateObjectMetadata(bucket, key string, contentType *string, metadata map[string]string) (*ObjectMetadata, error) {
+	return c.updateObjectMetadata("UpdateObjectMetadata", bucket, key, contentType, metadata)
+}
+
+func (c *Client) updateObjectMetadata(op, bucket, key string, contentType *string, metadata map[string]string, opts ...RequestOption) (*ObjectMetadata, error) {
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	reqBody := updateObjectMetadataRequest{ContentType: contentType, Metadata: metadata}
+	body, err := json.Marshal(reqBody)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	req, err := c.newObjectRequest(context.Background(), "PUT", "object-info", bucket, key, "", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	newRequestOptions(opts).apply(req)
+
+	resp, err := c.do(op, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, &Error{
-			StatusCode: resp.StatusCode,
-			Message:    string(bodyBytes),
-		}
+		return nil, c.newError(resp)
 	}
 
 	var objMetadata ObjectMetadata
 	if err := json.NewDecoder(resp.Body).Decode(&objMetadata); err != nil {
 		return nil, err
 	}
+	objMetadata.RequestID = resp.Header.Get("X-Request-Id")
 
 	return &objMetadata, nil
 }
 
-func (c *Client) DeleteObject(bucket, key string) error {
-	urlPath := fmt.Sprintf("%s/buckets/%s/objects/%s", c.baseURL, bucket, key)
-	req, err := http.NewRequest("DELETE", urlPath, nil)
+// UpdateObjectMetadataCT is UpdateObjectMetadata with a plain content type;
+// an empty contentType leaves it unset.
+func (c *Client) UpdateObjectMetadataCT(bucket, key string, contentType string, metadata map[string]string) (*ObjectMetadata, error) {
+	return c.UpdateObjectMetadata(bucket, key, optionalString(contentType), metadata)
+}
+
+func (c *Client) DeleteObject(bucket, key string, opts ...RequestOption) error {
+	req, err := c.newObjectRequest(context.Background(), "DELETE", "objects", bucket, key, "", nil)
 	if err != nil {
 		return err
 	}
+	options := newRequestOptions(opts)
+	options.apply(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("DeleteObject", req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound && options.ignoreNotFound {
+		return nil
+	}
+
 	if resp.StatusCode != http.StatusNoContent {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return &Error{
-			StatusCode: resp.StatusCode,
-			Message:    string(bodyBytes),
-		}
+		return c.newError(resp)
 	}
 
 	return nil
 }
 
 func (c *Client) ListObjects(bucket string, prefix *string, maxKeys *int) ([]ObjectMetadata, error) {
-	urlPath := fmt.Sprintf("%s/buckets/%s/objects", c.baseURL, bucket)
-
-	params := url.Values{}
+	var opts ListOptions
 	if prefix != nil {
-		params.Add("prefix", *prefix)
+		opts.Prefix = *prefix
 	}
 	if maxKeys != nil {
-		params.Add("max_keys", strconv.Itoa(*maxKeys))
+		opts.MaxKeys = *maxKeys
 	}
 
-	if len(params) > 0 {
-		urlPath += "?" + params.Encode()
-	}
-
-	req, err := http.NewRequest("GET", urlPath, nil)
+	result, err := c.List(bucket, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, &Error{
-			StatusCode: resp.StatusCode,
-			Message:    string(bodyBytes),
-		}
-	}
-
-	var result listObjectsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
-
 	return result.Objects, nil
 }
 
 func (c *Client) GetPublicURL(bucket, key string, expirationSecs *uint64, purpose *PublicUrlPurpose) (*PublicURLResponse, error) {
-	urlPath := fmt.Sprintf("%s/buckets/%s/public-url/%s", c.baseURL, bucket, key)
-
 	params := url.Values{}
 	if expirationSecs != nil {
 		params.Add("expiration_secs", strconv.FormatUint(*expirationSecs, 10))
@@ -507,27 +844,19 @@ func (c *Client) GetPublicURL(bucket, key string, expirationSecs *uint64, purpos
 		params.Add("purpose", string(*purpose))
 	}
 
-	if len(params) > 0 {
-		urlPath += "?" + params.Encode()
-	}
-
-	req, err := http.NewRequest("GET", urlPath, nil)
+	req, err := c.newObjectRequest(context.Background(), "GET", "public-url", bucket, key, params.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do("GetPublicURL", req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, &Error{
-			StatusCode: resp.StatusCode,
-			Message:    string(bodyBytes),
-		}
+		return nil, c.newError(resp)
 	}
 
 	var result PublicURLResponse
@@ -537,3 +866,37 @@ func (c *Client) GetPublicURL(bucket, key string, expirationSecs *uint64, purpos
 
 	return &result, nil
 }
+
+// Validate checks that a retrieve URL works before it is handed out, by
+// fetching its first byte through client with a GET and "Range: bytes=0-0";
+// backends sign retrieve URLs for GET only, so a HEAD would be rejected. It
+// fails if the URL has no remaining lifetime or the server does not answer
+// with 200 or 206 (or 416 for an empty object), which catches clock skew and
+// signing misconfiguration.
+//
+// Upload URLs are signed for PUT and cannot be validated this way; Validate
+// must not be called for them, as it would report them as rejected.
+func (r *PublicURLResponse) Validate(client *Client) error {
+	if r.ExpiresIn == 0 {
+		return fmt.Errorf("objectstorage: public URL has already expired")
+	}
+
+	req, err := http.NewRequest("GET", r.URL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Range", "bytes=0-0")
+
+	resp, err := client.do("ValidatePublicURL", req)
+	if err != nil {
+		return err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusPartialContent, http.StatusRequestedRangeNotSatisfiable:
+		drainBody(resp)
+		return nil
+	}
+	defer resp.Body.Close()
+	return fmt.Errorf("objectstorage: public URL rejected: %w", client.errorFromResponse(resp, http.StatusText(resp.StatusCode)))
+}
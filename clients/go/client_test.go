@@ -1,10 +1,13 @@
 package objectstorage
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -16,6 +19,42 @@ func TestNewClient(t *testing.T) {
 	assert.Equal(t, "http://localhost:8080", client.baseURL)
 }
 
+func TestClose(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	require.NoError(t, client.Ping())
+	require.NoError(t, client.Close())
+	require.NoError(t, client.Close())
+
+	assert.ErrorIs(t, client.Ping(), ErrClientClosed)
+	_, err := client.GetObject("test-bucket", "test-key")
+	assert.ErrorIs(t, err, ErrClientClosed)
+}
+
+func TestPingContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ping", r.URL.Path)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := client.PingContext(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
 func TestCreateBucket(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "POST", r.Method)
@@ -78,6 +117,22 @@ func TestListBuckets(t *testing.T) {
 	assert.Equal(t, "bucket2", buckets[1].Name)
 }
 
+func TestListBucketsWithPrefix(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "tenant-123-", r.URL.Query().Get("prefix"))
+		// Ignore the prefix, as a server without filtering support would.
+		json.NewEncoder(w).Encode(listBucketsResponse{
+			Buckets: []Bucket{{Name: "tenant-123-logs"}, {Name: "tenant-456-logs"}, {Name: "tenant-123-media"}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	buckets, err := client.ListBucketsWithPrefix("tenant-123-")
+	require.NoError(t, err)
+	assert.Equal(t, []Bucket{{Name: "tenant-123-logs"}, {Name: "tenant-123-media"}}, buckets)
+}
+
 func TestDeleteBucket(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "DELETE", r.Method)
@@ -135,6 +190,174 @@ func TestPutObject(t *testing.T) {
 	assert.Equal(t, "abc123", obj.ETag)
 }
 
+func TestPutObjectExpectedETag(t *testing.T) {
+	data := []byte("artifact")
+	etag := ComputeETag(data)
+	stored := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, etag, r.Header.Get("x-object-expected-etag"))
+		if stored {
+			w.Header().Set("ETag", `"`+etag+`"`)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		stored = true
+		json.NewEncoder(w).Encode(ObjectMetadata{Key: "test-key", Size: 8, ETag: etag})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.PutObject("test-bucket", "test-key", data, nil, nil, WithExpectedETag(`"`+etag+`"`))
+	require.NoError(t, err)
+	assert.False(t, obj.Skipped)
+
+	obj, err = client.PutObject("test-bucket", "test-key", data, nil, nil, WithExpectedETag(etag))
+	require.NoError(t, err)
+	assert.True(t, obj.Skipped)
+	assert.Equal(t, "test-key", obj.Key)
+	assert.Equal(t, uint64(8), obj.Size)
+	assert.True(t, ETagEqual(etag, obj.ETag))
+}
+
+func TestContentDisposition(t *testing.T) {
+	const disposition = `attachment; filename="report.pdf"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PUT":
+			assert.Equal(t, disposition, r.Header.Get("Content-Disposition"))
+			w.Write([]byte(`{"key":"report.pdf","content_disposition":"attachment; filename=\"report.pdf\""}`))
+		case "GET":
+			w.Header().Set("Content-Disposition", disposition)
+			w.Write([]byte("%PDF"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.PutObject("test-bucket", "report.pdf", []byte("%PDF"), nil, nil, WithContentDisposition(disposition))
+	require.NoError(t, err)
+	assert.Equal(t, disposition, obj.ContentDisposition)
+
+	data, err := client.GetObject("test-bucket", "report.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, disposition, data.Metadata.ContentDisposition)
+}
+
+func TestContentLanguage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PUT":
+			assert.Equal(t, "de-CH", r.Header.Get("Content-Language"))
+			w.Write([]byte(`{"key":"guide.html","contentLanguage":"de-CH"}`))
+		case "GET":
+			w.Header().Set("Content-Language", "de-CH")
+			w.Write([]byte("<html>"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.PutObject("test-bucket", "guide.html", []byte("<html>"), nil, nil, WithContentLanguage("de-CH"))
+	require.NoError(t, err)
+	assert.Equal(t, "de-CH", obj.ContentLanguage)
+
+	data, err := client.GetObject("test-bucket", "guide.html")
+	require.NoError(t, err)
+	assert.Equal(t, "de-CH", data.Metadata.ContentLanguage)
+}
+
+func TestWebsiteRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PUT":
+			assert.Equal(t, "/docs/v2/", r.Header.Get("x-object-website-redirect-location"))
+			w.Write([]byte(`{"key":"docs/index.html","website_redirect_location":"/docs/v2/"}`))
+		case "HEAD":
+			w.Header().Set("X-Object-Website-Redirect-Location", "/docs/v2/")
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.PutObject("test-bucket", "docs/index.html", nil, nil, nil, WithWebsiteRedirect("/docs/v2/"))
+	require.NoError(t, err)
+	assert.Equal(t, "/docs/v2/", obj.WebsiteRedirectLocation)
+
+	obj, err = client.HeadObject("test-bucket", "docs/index.html")
+	require.NoError(t, err)
+	assert.Equal(t, "/docs/v2/", obj.WebsiteRedirectLocation)
+}
+
+func TestCacheControl(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PUT":
+			assert.Equal(t, "public, max-age=3600", r.Header.Get("Cache-Control"))
+			w.Write([]byte(`{"key":"logo.png","cache_control":"public, max-age=3600"}`))
+		case "HEAD":
+			w.Header().Set("Cache-Control", "public, max-age=3600")
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.PutObject("test-bucket", "logo.png", []byte("png"), nil, nil, WithCacheControl("public, max-age=3600"))
+	require.NoError(t, err)
+	assert.Equal(t, "public, max-age=3600", obj.CacheControl)
+
+	obj, err = client.HeadObject("test-bucket", "logo.png")
+	require.NoError(t, err)
+	assert.Equal(t, "public, max-age=3600", obj.CacheControl)
+}
+
+func TestPutObjectCT(t *testing.T) {
+	var contentTypes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"key":"test-key"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.PutObjectCT("test-bucket", "test-key", []byte("data"), "text/plain", nil)
+	require.NoError(t, err)
+	_, err = client.PutObjectCT("test-bucket", "test-key", []byte("data"), "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"text/plain", ""}, contentTypes)
+}
+
+func TestPutObjectMetadataHeadersDeterministic(t *testing.T) {
+	var wire []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		r.Header.Write(&buf)
+		wire = append(wire, buf.String())
+		assert.Equal(t, []string{"lower"}, r.Header.Values("X-Object-Meta-Owner"))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"key":"test-key"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	metadata := map[string]string{
+		"owner": "lower",
+		"Owner": "upper",
+		"b":     "2",
+		"a":     "1",
+		"c":     "3",
+	}
+	for i := 0; i < 20; i++ {
+		_, err := client.PutObject("test-bucket", "test-key", []byte("data"), nil, metadata)
+		require.NoError(t, err)
+	}
+
+	for _, w := range wire[1:] {
+		assert.Equal(t, wire[0], w)
+	}
+}
+
 func TestGetObject(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "GET", r.Method)
@@ -171,6 +394,39 @@ func TestGetObjectNotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "404")
 }
 
+func TestGetObjectIfMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-Match") != `"abc123"` {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		w.Header().Set("ETag", "abc123")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("Hello, World!"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.GetObject("test-bucket", "test-key", WithIfMatch("abc123"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("Hello, World!"), obj.Data)
+
+	_, err = client.GetObject("test-bucket", "test-key", WithIfMatch("stale"))
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+}
+
+func TestGetObjectIfNoneMatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `"abc123"`, r.Header.Get("If-None-Match"))
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.GetObject("test-bucket", "test-key", WithIfNoneMatch("abc123"))
+	assert.ErrorIs(t, err, ErrNotModified)
+}
+
 func TestHeadObject(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "HEAD", r.Method)
@@ -192,6 +448,169 @@ func TestHeadObject(t *testing.T) {
 	assert.Equal(t, "abc123", obj.ETag)
 }
 
+func TestHeadObjectSystemMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-object-meta-owner", "alice")
+		w.Header().Set("x-object-created-by", "ingest-worker")
+		w.Header().Set("x-replication-status", "COMPLETED")
+		w.Header().Set("Server", "storage")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.HeadObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Owner": "alice"}, obj.Metadata)
+	assert.Equal(t, "ingest-worker", obj.SystemMetadata["X-Object-Created-By"])
+	assert.Equal(t, "COMPLETED", obj.SystemMetadata["X-Replication-Status"])
+	assert.NotContains(t, obj.SystemMetadata, "X-Object-Meta-Owner")
+	assert.NotContains(t, obj.SystemMetadata, "Server")
+}
+
+func TestHeadObjectConditional(t *testing.T) {
+	modified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "HEAD", r.Method)
+		w.Header().Set("ETag", `"abc123"`)
+		w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		if r.Header.Get("If-None-Match") == `"abc123"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !modified.After(since) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.HeadObject("test-bucket", "test-key", WithIfNoneMatch("abc123"))
+	assert.ErrorIs(t, err, ErrNotModified)
+
+	_, err = client.HeadObject("test-bucket", "test-key", WithIfModifiedSince(modified))
+	assert.ErrorIs(t, err, ErrNotModified)
+
+	obj, err := client.HeadObject("test-bucket", "test-key", WithIfModifiedSince(modified.Add(-time.Hour)))
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", obj.ETag)
+
+	// The unquoted ETag is quoted again in conditional headers.
+	_, err = client.HeadObject("test-bucket", "test-key", WithIfNoneMatch(obj.ETag))
+	assert.ErrorIs(t, err, ErrNotModified)
+}
+
+func TestETagUnquoted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/buckets/test-bucket/objects":
+			w.Write([]byte(`{"objects":[{"key":"a","etag":"\"abc123\""},{"key":"b","etag":"W/\"def456\""}]}`))
+		case r.Method == "PUT":
+			w.Write([]byte(`{"key":"a","etag":"\"abc123\""}`))
+		default:
+			w.Header().Set("ETag", `"abc123"`)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	put, err := client.PutObject("test-bucket", "a", []byte("x"), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", put.ETag)
+
+	head, err := client.HeadObject("test-bucket", "a")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", head.ETag)
+
+	get, err := client.GetObject("test-bucket", "a")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", get.Metadata.ETag)
+
+	objects, err := client.ListObjects("test-bucket", nil, nil)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "abc123", objects[0].ETag)
+	assert.Equal(t, "def456", objects[1].ETag)
+}
+
+func TestGetObjectInfoConditional(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GET", r.Method)
+		assert.Equal(t, "/buckets/test-bucket/object-info/test-key", r.URL.Path)
+		if r.Header.Get("If-None-Match") == `"abc123"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		json.NewEncoder(w).Encode(ObjectMetadata{Key: "test-key", ETag: "abc123"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.GetObjectInfo("test-bucket", "test-key")
+	require.NoError(t, err)
+
+	_, err = client.GetObjectInfo("test-bucket", "test-key", WithIfNoneMatch(obj.ETag))
+	assert.ErrorIs(t, err, ErrNotModified)
+
+	obj, err = client.GetObjectInfo("test-bucket", "test-key", WithIfNoneMatch("stale"))
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", obj.ETag)
+}
+
+func TestGetObjectInfoRaw(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/buckets/test-bucket/object-info/test-key", r.URL.Path)
+		w.Write([]byte(`{"key":"test-key","size":5,"replicas":3}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	raw, obj, err := client.GetObjectInfoRaw("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), obj.Size)
+
+	var extra struct {
+		Replicas int `json:"replicas"`
+	}
+	require.NoError(t, json.Unmarshal(raw, &extra))
+	assert.Equal(t, 3, extra.Replicas)
+}
+
+func TestUpdateObjectMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/buckets/test-bucket/object-info/test-key", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var req updateObjectMetadataRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+		require.NotNil(t, req.ContentType)
+		assert.Equal(t, "application/pdf", *req.ContentType)
+		assert.Equal(t, map[string]string{"owner": "alice"}, req.Metadata)
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(ObjectMetadata{
+			Key:          "test-key",
+			Size:         13,
+			ContentType:  req.ContentType,
+			ETag:         "abc123",
+			LastModified: "2024-01-02T00:00:00Z",
+			Metadata:     req.Metadata,
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.UpdateObjectMetadata("test-bucket", "test-key", stringPtr("application/pdf"), map[string]string{"owner": "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, "application/pdf", *obj.ContentType)
+	assert.Equal(t, "alice", obj.Metadata["owner"])
+	assert.Equal(t, "2024-01-02T00:00:00Z", obj.LastModified)
+}
+
 func TestDeleteObject(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "DELETE", r.Method)
@@ -205,6 +624,21 @@ func TestDeleteObject(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestDeleteObjectIgnoreNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Object not found"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	err := client.DeleteObject("test-bucket", "test-key")
+	assert.Error(t, err)
+
+	err = client.DeleteObject("test-bucket", "test-key", WithIgnoreNotFound())
+	assert.NoError(t, err)
+}
+
 func TestListObjects(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, "GET", r.Method)
@@ -267,7 +701,7 @@ func TestGetPublicURL(t *testing.T) {
 
 	client := NewClient(server.URL)
 	expirationSecs := uint64(7200)
-	response, err := client.GetPublicURL("test-bucket", "test-key", &expirationSecs)
+	response, err := client.GetPublicURL("test-bucket", "test-key", &expirationSecs, nil)
 	require.NoError(t, err)
 	assert.Equal(t, "https://example.com/signed-url?signature=abc123", response.URL)
 	assert.Equal(t, uint64(7200), response.ExpiresIn)
@@ -288,7 +722,7 @@ func TestGetPublicURLDefaultExpiration(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL)
-	response, err := client.GetPublicURL("test-bucket", "test-key", nil)
+	response, err := client.GetPublicURL("test-bucket", "test-key", nil, nil)
 	require.NoError(t, err)
 	assert.Equal(t, "https://example.com/signed-url?signature=xyz789", response.URL)
 	assert.Equal(t, uint64(3600), response.ExpiresIn)
@@ -302,11 +736,37 @@ func TestGetPublicURLNotFound(t *testing.T) {
 	defer server.Close()
 
 	client := NewClient(server.URL)
-	_, err := client.GetPublicURL("test-bucket", "test-key", nil)
+	_, err := client.GetPublicURL("test-bucket", "test-key", nil, nil)
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "Object not found")
 }
 
+func TestPublicURLValidate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Like S3 and GCS, the URL is signed for GET only.
+		if r.Method != "GET" || r.URL.Query().Get("sig") != "good" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		assert.Equal(t, "bytes=0-0", r.Header.Get("Range"))
+		serveContent([]byte("Hello, World!"), `"abc123"`, nil).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	good := &PublicURLResponse{URL: server.URL + "/public/test-key?sig=good", ExpiresIn: 3600}
+	assert.NoError(t, good.Validate(client))
+
+	bad := &PublicURLResponse{URL: server.URL + "/public/test-key?sig=skewed", ExpiresIn: 3600}
+	err := bad.Validate(client)
+	var objErr *Error
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, http.StatusForbidden, objErr.StatusCode)
+
+	expired := &PublicURLResponse{URL: good.URL}
+	assert.Error(t, expired.Validate(client))
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
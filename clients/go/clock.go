@@ -0,0 +1,50 @@
+package objectstorage
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the source of time for retry backoff, Retry-After dates, rate
+// limiting, the circuit breaker, read-after-write retries, restore polling,
+// latency statistics and the progress reported by the client's helpers.
+// Tests can install a fake one with WithClock to exercise that behaviour
+// without real sleeps.
+type Clock interface {
+	Now() time.Time
+	// Sleep waits for d, returning early with ctx.Err() if ctx is done.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	return sleepContext(ctx, d)
+}
+
+// WithClock replaces the real clock. It is intended for tests.
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		c.clock = clock
+	}
+}
+
+// timeSource returns the client's Clock.
+func (c *Client) timeSource() Clock {
+	if c.clock == nil {
+		return realClock{}
+	}
+	return c.clock
+}
+
+func (c *Client) now() time.Time {
+	return c.timeSource().Now()
+}
+
+func (c *Client) sleep(ctx context.Context, d time.Duration) error {
+	return c.timeSource().Sleep(ctx, d)
+}
@@ -0,0 +1,103 @@
+package objectstorage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock advances only when slept on or when Advance is called.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sleeps = append(f.sleeps, d)
+	f.now = f.now.Add(d)
+	return ctx.Err()
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func TestWithClockRetryBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	clock := newFakeClock()
+	client := NewClient(server.URL,
+		WithClock(clock),
+		WithRetry(RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Second, MaxBackoff: time.Minute}),
+	)
+	require.Error(t, client.Ping())
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, clock.sleeps)
+}
+
+func TestWithClockCircuitBreakerCooldown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	clock := newFakeClock()
+	client := NewClient(server.URL, WithClock(clock), WithCircuitBreaker(1, time.Hour))
+	assert.NotErrorIs(t, client.Ping(), ErrCircuitOpen)
+	assert.ErrorIs(t, client.Ping(), ErrCircuitOpen)
+
+	clock.Advance(59 * time.Minute)
+	assert.ErrorIs(t, client.Ping(), ErrCircuitOpen)
+	clock.Advance(time.Minute)
+	assert.NotErrorIs(t, client.Ping(), ErrCircuitOpen)
+}
+
+func TestWithClockRetryAfterDate(t *testing.T) {
+	clock := newFakeClock()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", clock.Now().Add(90*time.Second).Format(http.TimeFormat))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithClock(clock))
+	_, err := client.HeadObject("test-bucket", "test-key")
+	var objErr *Error
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, 90*time.Second, objErr.RetryAfter)
+}
+
+func TestWithClockStatsLatency(t *testing.T) {
+	clock := newFakeClock()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clock.Advance(250 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithClock(clock), WithStats())
+	require.NoError(t, client.Ping())
+	assert.Equal(t, 250*time.Millisecond, client.Stats()["Ping"].P50)
+}
@@ -0,0 +1,114 @@
+package objectstorage
+
+import (
+	"bytes"
+	"errors"
+	"maps"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// WithRequestCoalescing makes concurrent GetObject calls for the same object
+// with the same options share one request: while a download is in flight,
+// identical calls wait for it instead of sending their own, and each receives
+// its own copy of the result, or the same error. Calls that must observe
+// writes made after an in-flight download started can opt out with
+// WithoutCoalescing.
+func WithRequestCoalescing() Option {
+	return func(c *Client) {
+		c.coalescer = &coalescer{calls: make(map[string]*coalescedCall)}
+	}
+}
+
+// WithoutCoalescing makes a GetObject call send its own request even if an
+// identical one is in flight, when WithRequestCoalescing is enabled.
+func WithoutCoalescing() RequestOption {
+	return func(o *requestOptions) {
+		o.noCoalesce = true
+	}
+}
+
+var errCoalescedPanic = errors.New("objectstorage: coalesced GetObject panicked")
+
+type coalescer struct {
+	mu    sync.Mutex
+	calls map[string]*coalescedCall
+}
+
+type coalescedCall struct {
+	done chan struct{}
+	// obj is never handed out itself, so that no caller can modify it while
+	// others are still copying it.
+	obj *ObjectData
+	err error
+}
+
+// do calls fn, unless a call with the same key is in flight, in which case
+// it waits for that call. Every caller, including the one that ran fn,
+// receives its own copy of the result.
+func (g *coalescer) do(key string, fn func() (*ObjectData, error)) (*ObjectData, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		<-call.done
+		if call.err != nil {
+			return nil, call.err
+		}
+		return cloneObjectData(call.obj), nil
+	}
+	// Waiters see errCoalescedPanic if fn panics; the panic itself
+	// continues in this goroutine.
+	call := &coalescedCall{done: make(chan struct{}), err: errCoalescedPanic}
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.obj, call.err = fn()
+	if call.err != nil {
+		return nil, call.err
+	}
+	return cloneObjectData(call.obj), nil
+}
+
+// coalesceKey identifies a GetObject call by object and by everything in its
+// options that can change the response.
+func coalesceKey(bucket, key string, options *requestOptions) string {
+	var b strings.Builder
+	b.WriteString(bucket)
+	b.WriteByte(0)
+	b.WriteString(key)
+	b.WriteByte(0)
+	if options.verifyCRC32C {
+		b.WriteString("crc32c")
+	}
+
+	names := make([]string, 0, len(options.header))
+	for name := range options.header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		b.WriteByte(0)
+		b.WriteString(name)
+		b.WriteByte(':')
+		b.WriteString(strings.Join(options.header[name], ","))
+	}
+	return b.String()
+}
+
+// cloneObjectData copies obj so that callers sharing a coalesced download
+// cannot observe each other's modifications.
+func cloneObjectData(obj *ObjectData) *ObjectData {
+	clone := *obj
+	clone.Data = bytes.Clone(obj.Data)
+	clone.Metadata.Metadata = maps.Clone(obj.Metadata.Metadata)
+	clone.Metadata.SystemMetadata = maps.Clone(obj.Metadata.SystemMetadata)
+	return &clone
+}
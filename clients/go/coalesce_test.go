@@ -0,0 +1,139 @@
+package objectstorage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestCoalescing(t *testing.T) {
+	var hits atomic.Int32
+	started := make(chan struct{}, 10)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		started <- struct{}{}
+		<-release
+		w.Header().Set("X-Object-Meta-Owner", "ana")
+		w.Write([]byte("hot object"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRequestCoalescing())
+
+	results := make([]*ObjectData, 5)
+	var wg sync.WaitGroup
+	get := func(i int) {
+		defer wg.Done()
+		obj, err := client.GetObject("test-bucket", "hot")
+		assert.NoError(t, err)
+		results[i] = obj
+	}
+
+	wg.Add(1)
+	go get(0)
+	<-started
+	for i := 1; i < len(results); i++ {
+		wg.Add(1)
+		go get(i)
+	}
+	// Give the followers time to join the in-flight request.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), hits.Load())
+	for _, obj := range results {
+		assert.Equal(t, "hot object", string(obj.Data))
+		assert.Equal(t, "ana", obj.Metadata.Metadata["Owner"])
+	}
+	// Each caller owns its copy.
+	results[1].Data[0] = 'H'
+	assert.Equal(t, "hot object", string(results[2].Data))
+
+	// Sequential calls and opted-out calls are not coalesced.
+	_, err := client.GetObject("test-bucket", "hot")
+	require.NoError(t, err)
+	_, err = client.GetObject("test-bucket", "hot", WithoutCoalescing())
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), hits.Load())
+}
+
+func TestCoalesceKey(t *testing.T) {
+	plain := coalesceKey("b", "k", newRequestOptions(nil))
+	assert.Equal(t, plain, coalesceKey("b", "k", newRequestOptions(nil)))
+	assert.NotEqual(t, plain, coalesceKey("b", "k2", newRequestOptions(nil)))
+	assert.NotEqual(t, plain, coalesceKey("b", "k", newRequestOptions([]RequestOption{WithIfNoneMatch("e1")})))
+	assert.NotEqual(t, plain, coalesceKey("b", "k", newRequestOptions([]RequestOption{WithChecksumVerification()})))
+}
+
+func TestCoalescerPanic(t *testing.T) {
+	g := &coalescer{calls: make(map[string]*coalescedCall)}
+	entered := make(chan struct{})
+
+	waited := make(chan error)
+	go func() {
+		<-entered
+		_, err := g.do("k", func() (*ObjectData, error) {
+			t.Error("waiter ran its own call")
+			return nil, nil
+		})
+		waited <- err
+	}()
+
+	assert.Panics(t, func() {
+		g.do("k", func() (*ObjectData, error) {
+			close(entered)
+			// Give the waiter time to join the call.
+			time.Sleep(100 * time.Millisecond)
+			panic("boom")
+		})
+	})
+	assert.ErrorIs(t, <-waited, errCoalescedPanic)
+
+	// The key is free again; later calls do not block.
+	obj, err := g.do("k", func() (*ObjectData, error) { return &ObjectData{Data: []byte("ok")}, nil })
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ok"), obj.Data)
+}
+
+func TestCoalescerLeaderGetsCopy(t *testing.T) {
+	g := &coalescer{calls: make(map[string]*coalescedCall)}
+	entered := make(chan struct{})
+
+	var wg sync.WaitGroup
+	waiters := make([]*ObjectData, 4)
+	for i := range waiters {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-entered
+			obj, err := g.do("k", func() (*ObjectData, error) { return &ObjectData{Data: []byte("other")}, nil })
+			assert.NoError(t, err)
+			waiters[i] = obj
+		}(i)
+	}
+
+	obj, err := g.do("k", func() (*ObjectData, error) {
+		close(entered)
+		// Give the waiters time to join the call.
+		time.Sleep(50 * time.Millisecond)
+		return &ObjectData{Data: []byte("shared"), Metadata: ObjectMetadata{Metadata: map[string]string{"Owner": "ana"}}}, nil
+	})
+	require.NoError(t, err)
+	// The leader modifies its result while the waiters copy theirs.
+	obj.Data[0] = 'S'
+	obj.Metadata.Metadata["Owner"] = "bo"
+	wg.Wait()
+
+	for _, w := range waiters {
+		assert.Equal(t, "shared", string(w.Data))
+		assert.Equal(t, "ana", w.Metadata.Metadata["Owner"])
+	}
+}
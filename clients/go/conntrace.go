@@ -0,0 +1,86 @@
+package objectstorage
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// ConnStats aggregates connection-level timings across requests.
+type ConnStats struct {
+	// Requests is the number of requests that obtained a connection.
+	Requests int64
+	// ReusedConns counts requests served over an existing keep-alive
+	// connection; NewConns counts requests that had to dial.
+	ReusedConns int64
+	NewConns    int64
+	// DNSLookups and TLSHandshakes count the lookups and handshakes
+	// performed, with their cumulative durations.
+	DNSLookups    int64
+	DNSTime       time.Duration
+	TLSHandshakes int64
+	TLSTime       time.Duration
+}
+
+type connTracer struct {
+	mu    sync.Mutex
+	stats ConnStats
+}
+
+// WithConnTrace enables httptrace-based instrumentation of connection reuse,
+// DNS lookups and TLS handshakes. Read the results with ConnStats.
+func WithConnTrace() Option {
+	return func(c *Client) {
+		c.connTrace = &connTracer{}
+	}
+}
+
+// ConnStats returns the connection statistics gathered so far. It returns the
+// zero value unless the client was created with WithConnTrace.
+func (c *Client) ConnStats() ConnStats {
+	if c.connTrace == nil {
+		return ConnStats{}
+	}
+
+	c.connTrace.mu.Lock()
+	defer c.connTrace.mu.Unlock()
+	return c.connTrace.stats
+}
+
+// trace returns a copy of req that reports its connection events to t.
+func (t *connTracer) trace(req *http.Request) *http.Request {
+	var dnsStart, tlsStart time.Time
+	ct := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.stats.Requests++
+			if info.Reused {
+				t.stats.ReusedConns++
+			} else {
+				t.stats.NewConns++
+			}
+		},
+		DNSStart: func(httptrace.DNSStartInfo) {
+			dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.stats.DNSLookups++
+			t.stats.DNSTime += time.Since(dnsStart)
+		},
+		TLSHandshakeStart: func() {
+			tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.stats.TLSHandshakes++
+			t.stats.TLSTime += time.Since(tlsStart)
+		},
+	}
+	return req.WithContext(httptrace.WithClientTrace(req.Context(), ct))
+}
@@ -0,0 +1,38 @@
+package objectstorage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnTrace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithConnTrace())
+	for i := 0; i < 3; i++ {
+		require.NoError(t, client.Ping())
+	}
+
+	stats := client.ConnStats()
+	assert.Equal(t, int64(3), stats.Requests)
+	assert.Equal(t, int64(1), stats.NewConns)
+	assert.Equal(t, int64(2), stats.ReusedConns)
+}
+
+func TestConnTraceDisabled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	require.NoError(t, client.Ping())
+	assert.Equal(t, ConnStats{}, client.ConnStats())
+}
@@ -0,0 +1,112 @@
+package objectstorage
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// readAfterWriteWindow is how long after a write a 404 for the same object
+// is attributed to replication lag rather than the object being missing.
+const readAfterWriteWindow = 30 * time.Second
+
+// WithReadAfterWriteRetry smooths over the consistency window of
+// eventually-consistent backends: when a GET or HEAD of an object this client
+// wrote within the last 30 seconds returns 404, it is retried up to attempts
+// more times, delay apart, before the 404 is returned.
+//
+// This is independent of WithRetry, which never retries 404. Objects written
+// by other clients are not tracked.
+func WithReadAfterWriteRetry(attempts int, delay time.Duration) Option {
+	return func(c *Client) {
+		c.readAfterWrite = &writeTracker{
+			attempts: attempts,
+			delay:    delay,
+			writes:   make(map[string]time.Time),
+		}
+	}
+}
+
+type writeTracker struct {
+	attempts int
+	delay    time.Duration
+
+	mu     sync.Mutex
+	writes map[string]time.Time
+}
+
+// handle records successful object writes, forgets them again on a
+// successful delete, and retries reads of recently written objects that come
+// back as 404, calling resend for each retry.
+func (w *writeTracker) handle(clock Clock, req *http.Request, resp *http.Response, resend func() (*http.Response, error)) (*http.Response, error) {
+	id, ok := objectID(req)
+	if !ok {
+		return resp, nil
+	}
+
+	switch req.Method {
+	case http.MethodPut:
+		if resp.StatusCode == http.StatusOK {
+			w.record(id, clock.Now())
+		}
+		return resp, nil
+	case http.MethodDelete:
+		// A 404 after a delete is expected, not replication lag.
+		if resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusOK {
+			w.forget(id)
+		}
+		return resp, nil
+	case http.MethodGet, http.MethodHead:
+	default:
+		return resp, nil
+	}
+
+	for i := 0; i < w.attempts && resp.StatusCode == http.StatusNotFound && w.recent(id, clock.Now()); i++ {
+		drainBody(resp)
+		if err := clock.Sleep(req.Context(), w.delay); err != nil {
+			return nil, err
+		}
+
+		var err error
+		resp, err = resend()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (w *writeTracker) record(id string, now time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for k, t := range w.writes {
+		if now.Sub(t) > readAfterWriteWindow {
+			delete(w.writes, k)
+		}
+	}
+	w.writes[id] = now
+}
+
+func (w *writeTracker) forget(id string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.writes, id)
+}
+
+func (w *writeTracker) recent(id string, now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	t, ok := w.writes[id]
+	return ok && now.Sub(t) <= readAfterWriteWindow
+}
+
+// objectID returns the "bucket/key" of the object addressed by req, as
+// recorded by newObjectRequest, treating the objects and object-info
+// endpoints alike.
+func objectID(req *http.Request) (string, bool) {
+	id, ok := req.Context().Value(objectIDKey{}).(string)
+	return id, ok
+}
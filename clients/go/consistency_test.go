@@ -0,0 +1,122 @@
+package objectstorage
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// laggingServer stores objects but only serves them after lag further reads.
+func laggingServer(lag int) (*httptest.Server, *int) {
+	var reads int
+	stored := make(map[string]bool)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "PUT":
+			stored[r.URL.Path] = true
+			w.Write([]byte(`{"key":"test-key"}`))
+		case "GET":
+			reads++
+			if !stored[r.URL.Path] || reads <= lag {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte("data"))
+		case "DELETE":
+			delete(stored, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	return server, &reads
+}
+
+func TestReadAfterWriteRetry(t *testing.T) {
+	server, reads := laggingServer(2)
+	defer server.Close()
+
+	client := NewClient(server.URL, WithReadAfterWriteRetry(3, time.Millisecond))
+	_, err := client.PutObject("test-bucket", "test-key", []byte("data"), nil, nil)
+	require.NoError(t, err)
+
+	obj, err := client.GetObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), obj.Data)
+	assert.Equal(t, 3, *reads)
+}
+
+func TestReadAfterWriteRetryOnlyForWrittenKeys(t *testing.T) {
+	server, reads := laggingServer(0)
+	defer server.Close()
+
+	client := NewClient(server.URL, WithReadAfterWriteRetry(3, time.Millisecond))
+	_, err := client.GetObject("test-bucket", "other-key")
+	assert.Error(t, err)
+	assert.Equal(t, 1, *reads)
+}
+
+func TestReadAfterWriteRetryGivesUp(t *testing.T) {
+	server, reads := laggingServer(10)
+	defer server.Close()
+
+	client := NewClient(server.URL, WithReadAfterWriteRetry(2, time.Millisecond))
+	_, err := client.PutObject("test-bucket", "test-key", []byte("data"), nil, nil)
+	require.NoError(t, err)
+
+	_, err = client.GetObject("test-bucket", "test-key")
+	var objErr *Error
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, http.StatusNotFound, objErr.StatusCode)
+	assert.Equal(t, 3, *reads)
+}
+
+func TestReadAfterWriteRetryForgetsDeleted(t *testing.T) {
+	server, reads := laggingServer(0)
+	defer server.Close()
+
+	client := NewClient(server.URL, WithReadAfterWriteRetry(3, time.Millisecond))
+	_, err := client.PutObject("test-bucket", "test-key", []byte("data"), nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, client.DeleteObject("test-bucket", "test-key"))
+
+	_, err = client.GetObject("test-bucket", "test-key")
+	assert.True(t, isNotFound(err))
+	assert.Equal(t, 1, *reads)
+}
+
+func TestReadAfterWriteRetryVirtualHosted(t *testing.T) {
+	var reads int
+	stored := make(map[string]bool)
+	httpClient := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, "test-bucket.storage.example.com", req.URL.Host)
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Request: req}
+		switch req.Method {
+		case "PUT":
+			stored[req.URL.Path] = true
+			resp.Body = io.NopCloser(strings.NewReader(`{"key":"test-key"}`))
+		case "GET":
+			reads++
+			resp.Body = io.NopCloser(strings.NewReader("data"))
+			if !stored[req.URL.Path] || reads <= 2 {
+				resp.StatusCode = http.StatusNotFound
+				resp.Body = io.NopCloser(strings.NewReader(""))
+			}
+		}
+		return resp, nil
+	})}
+
+	client := NewClientWithHTTP("https://storage.example.com", httpClient,
+		WithVirtualHostedStyle(), WithReadAfterWriteRetry(3, time.Millisecond))
+	_, err := client.PutObject("test-bucket", "test-key", []byte("data"), nil, nil)
+	require.NoError(t, err)
+
+	obj, err := client.GetObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("data"), obj.Data)
+	assert.Equal(t, 3, reads)
+}
@@ -0,0 +1,204 @@
+package objectstorage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrCopyMismatch is returned when a copied object does not have the size
+// or ETag of the source version that was read.
+var ErrCopyMismatch = errors.New("objectstorage: copy does not match source")
+
+// copiedHeaders are the response headers of a source object that are sent
+// again when it is copied through the client.
+var copiedHeaders = []string{
+	"Content-Type",
+	"Content-Encoding",
+	"Content-Language",
+	"Content-Disposition",
+	"Cache-Control",
+	"X-Object-Storage-Class",
+	"X-Object-Website-Redirect-Location",
+}
+
+// CopyResult describes a completed copy.
+type CopyResult struct {
+	// Object is the metadata of the destination object.
+	Object ObjectMetadata
+	// SourceETag and SourceSize are the ETag and size of the source version
+	// that was copied.
+	SourceETag string
+	SourceSize uint64
+	// ServerSide reports whether the server copied the object itself. If
+	// false, the content was downloaded and uploaded again by the client.
+	ServerSide bool
+}
+
+// CopyObject copies an object. If Capabilities has reported that the server
+// supports server-side copies, the copy is made there without transferring
+// the data through the client, with the source pinned by a copy-source
+// If-Match on its current ETag. Otherwise the source is streamed from a
+// download into an upload, without being buffered, along with its content
+// type, encoding and metadata; servers that do not implement copies would
+// store an empty object if sent a copy request.
+//
+// Either way the destination is verified: if its size or ETag differs from
+// the source version that was read, or a streamed source does not hash to
+// its SHA-256 ETag, CopyObject fails with an error wrapping
+// ErrCopyMismatch. Options apply to the request that writes the destination.
+func (c *Client) CopyObject(srcBucket, srcKey, dstBucket, dstKey string, opts ...RequestOption) (*CopyResult, error) {
+	if c.requireConfirmedCapability(supportsCopy) == nil {
+		return c.copyObjectServerSide(srcBucket, srcKey, dstBucket, dstKey, opts)
+	}
+	return c.copyObjectThroughClient(srcBucket, srcKey, dstBucket, dstKey, opts)
+}
+
+func (c *Client) copyObjectServerSide(srcBucket, srcKey, dstBucket, dstKey string, opts []RequestOption) (*CopyResult, error) {
+	src, err := c.HeadObject(srcBucket, srcKey)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "PUT", "objects", dstBucket, dstKey, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-object-copy-source", srcBucket+"/"+c.keyEncoding.encode(srcKey))
+	if src.ETag != "" {
+		req.Header.Set("x-object-copy-source-if-match", quoteETag(src.ETag))
+	}
+	newRequestOptions(opts).apply(req)
+
+	resp, err := c.do("CopyObject", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.newError(resp)
+	}
+
+	var objMetadata ObjectMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&objMetadata); err != nil {
+		return nil, err
+	}
+	objMetadata.RequestID = resp.Header.Get("X-Request-Id")
+
+	if err := verifyCopy(src.Size, src.ETag, &objMetadata); err != nil {
+		return nil, err
+	}
+	return &CopyResult{Object: objMetadata, SourceETag: src.ETag, SourceSize: src.Size, ServerSide: true}, nil
+}
+
+func (c *Client) copyObjectThroughClient(srcBucket, srcKey, dstBucket, dstKey string, opts []RequestOption) (*CopyResult, error) {
+	req, err := c.newObjectRequest(context.Background(), "GET", "objects", srcBucket, srcKey, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	// The stored bytes are copied, so nothing may be decoded on the way.
+	acceptIdentity(req)
+
+	resp, err := c.do("CopyObject", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.newError(resp)
+	}
+	src := metadataFromHeaders(srcKey, resp.Header)
+
+	// The download is streamed into the upload. A body shorter or longer
+	// than the source's Content-Length aborts the upload.
+	body := &countingReader{r: resp.Body, size: resp.ContentLength, hash: sha256.New()}
+	req, err = c.newObjectRequest(context.Background(), "PUT", "objects", dstBucket, dstKey, "", body)
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = resp.ContentLength
+	for _, name := range copiedHeaders {
+		if v := resp.Header.Get(name); v != "" {
+			req.Header.Set(name, v)
+		}
+	}
+	setMetadataHeaders(req.Header, src.Metadata)
+	newRequestOptions(opts).apply(req)
+
+	put, err := c.do("CopyObject", req)
+	if err != nil {
+		if bodyErr := body.failed(); bodyErr != nil {
+			return nil, bodyErr
+		}
+		return nil, err
+	}
+	defer put.Body.Close()
+
+	if bodyErr := body.failed(); bodyErr != nil {
+		return nil, bodyErr
+	}
+	if put.StatusCode != http.StatusOK {
+		return nil, c.newError(put)
+	}
+	if bodyErr := body.check(); bodyErr != nil {
+		return nil, bodyErr
+	}
+	n, digest := body.sum()
+
+	var objMetadata ObjectMetadata
+	if err := json.NewDecoder(put.Body).Decode(&objMetadata); err != nil {
+		return nil, err
+	}
+	objMetadata.RequestID = put.Header.Get("X-Request-Id")
+
+	// A source ETag of SHA-256 length is the content hash, as on this
+	// project's server, so it must match what was streamed.
+	if etag := opaqueETag(src.ETag); len(etag) == 2*sha256.Size && etag != hex.EncodeToString(digest) {
+		return nil, fmt.Errorf("%w: read content with SHA-256 %x, source has ETag %q", ErrCopyMismatch, digest, src.ETag)
+	}
+	if err := verifyCopy(uint64(n), src.ETag, &objMetadata); err != nil {
+		return nil, err
+	}
+	return &CopyResult{Object: objMetadata, SourceETag: src.ETag, SourceSize: uint64(n)}, nil
+}
+
+// verifyCopy checks that dst has the given size and, if the source has one,
+// the source's ETag.
+func verifyCopy(size uint64, etag string, dst *ObjectMetadata) error {
+	if dst.Size != size {
+		return fmt.Errorf("%w: copied %d bytes, source has %d", ErrCopyMismatch, dst.Size, size)
+	}
+	if etag != "" && !ETagEqual(etag, dst.ETag) {
+		return fmt.Errorf("%w: copy has ETag %q, source has %q", ErrCopyMismatch, dst.ETag, etag)
+	}
+	return nil
+}
+
+// TouchObject refreshes the last-modified time of an object without changing
+// its content or metadata, by writing its current content type and metadata
+// back with a metadata update; the data is neither copied nor re-uploaded.
+// It returns the updated metadata. The write is conditional on the ETag that
+// was read, so it fails with ErrPreconditionFailed if the object is replaced
+// concurrently.
+func (c *Client) TouchObject(bucket, key string) (*ObjectMetadata, error) {
+	_, current, err := c.getObjectInfo("TouchObject", bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []RequestOption
+	if current.ETag != "" {
+		opts = append(opts, WithIfMatch(current.ETag))
+	}
+	return c.updateObjectMetadata("TouchObject", bucket, key, current.ContentType, current.Metadata, opts...)
+}
+
+func supportsCopy(s *ServerCapabilities) bool {
+	return s.Copy
+}
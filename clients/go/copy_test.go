@@ -0,0 +1,289 @@
+package objectstorage
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memObject struct {
+	data        []byte
+	contentType string
+	metadata    map[string]string
+}
+
+// memObjectServer is an in-memory object store routed like the real server:
+// PUT stores the request body and ignores x-object-copy-source, so a
+// body-less copy request stores an empty object. With serverCopy set, PUTs
+// carrying x-object-copy-source copy the source instead. ETags are the
+// SHA-256 of the content.
+type memObjectServer struct {
+	*httptest.Server
+	mu         sync.Mutex
+	objects    map[string]*memObject
+	serverCopy bool
+	requests   []string
+}
+
+func newMemObjectServer(t *testing.T) *memObjectServer {
+	s := &memObjectServer{objects: map[string]*memObject{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+		path := strings.TrimPrefix(r.URL.Path, "/buckets/")
+		bucket, key, ok := strings.Cut(path, "/objects/")
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		name := bucket + "/" + key
+
+		switch r.Method {
+		case "PUT":
+			obj := &memObject{contentType: r.Header.Get("Content-Type"), metadata: map[string]string{}}
+			if src := r.Header.Get("x-object-copy-source"); s.serverCopy && src != "" {
+				source, ok := s.objects[src]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				*obj = *source
+			} else {
+				data, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				obj.data = data
+				for name, values := range r.Header {
+					if meta, ok := strings.CutPrefix(name, "X-Object-Meta-"); ok {
+						obj.metadata[meta] = values[0]
+					}
+				}
+			}
+			s.objects[name] = obj
+			json.NewEncoder(w).Encode(ObjectMetadata{
+				Key:      key,
+				Size:     uint64(len(obj.data)),
+				ETag:     ComputeETag(obj.data),
+				Metadata: obj.metadata,
+			})
+		case "GET", "HEAD":
+			obj, ok := s.objects[name]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("ETag", ComputeETag(obj.data))
+			if obj.contentType != "" {
+				w.Header().Set("Content-Type", obj.contentType)
+			}
+			for k, v := range obj.metadata {
+				w.Header().Set("x-object-meta-"+k, v)
+			}
+			w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+			if r.Method == "GET" {
+				w.Write(obj.data)
+			}
+		case "DELETE":
+			delete(s.objects, name)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	return s
+}
+
+func (s *memObjectServer) put(name string, data string, metadata map[string]string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[name] = &memObject{data: []byte(data), contentType: "text/plain", metadata: metadata}
+}
+
+func (s *memObjectServer) get(name string) *memObject {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.objects[name]
+}
+
+func TestCopyObject(t *testing.T) {
+	server := newMemObjectServer(t)
+	defer server.Close()
+	server.put("src-bucket/src-key", "hello", map[string]string{"owner": "alice"})
+
+	client := NewClient(server.URL)
+	result, err := client.CopyObject("src-bucket", "src-key", "dst-bucket", "dst-key")
+	require.NoError(t, err)
+	assert.False(t, result.ServerSide)
+	assert.Equal(t, "dst-key", result.Object.Key)
+	assert.Equal(t, ComputeETag([]byte("hello")), result.SourceETag)
+
+	copied := server.get("dst-bucket/dst-key")
+	require.NotNil(t, copied)
+	assert.Equal(t, "hello", string(copied.data))
+	assert.Equal(t, "text/plain", copied.contentType)
+	assert.Equal(t, map[string]string{"Owner": "alice"}, copied.metadata)
+	// Without a capability no copy request is sent that the server would
+	// store as an empty object.
+	assert.Equal(t, []string{
+		"GET /buckets/src-bucket/objects/src-key",
+		"PUT /buckets/dst-bucket/objects/dst-key",
+	}, server.requests)
+}
+
+func TestCopyObjectServerSide(t *testing.T) {
+	server := newMemObjectServer(t)
+	defer server.Close()
+	server.serverCopy = true
+	server.put("src-bucket/src-key", "hello", nil)
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{Copy: true})
+	result, err := client.CopyObject("src-bucket", "src-key", "dst-bucket", "dst-key")
+	require.NoError(t, err)
+	assert.True(t, result.ServerSide)
+	assert.Equal(t, "hello", string(server.get("dst-bucket/dst-key").data))
+	assert.Equal(t, []string{
+		"HEAD /buckets/src-bucket/objects/src-key",
+		"PUT /buckets/dst-bucket/objects/dst-key",
+	}, server.requests)
+}
+
+func TestCopyObjectMismatch(t *testing.T) {
+	// A server that claims copy support but stores the empty request body.
+	server := newMemObjectServer(t)
+	defer server.Close()
+	server.put("src-bucket/src-key", "hello", nil)
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{Copy: true})
+	_, err := client.CopyObject("src-bucket", "src-key", "dst-bucket", "dst-key")
+	assert.ErrorIs(t, err, ErrCopyMismatch)
+}
+
+func TestCopyObjectTruncatedSource(t *testing.T) {
+	var stored atomic.Bool
+	content := serveContent([]byte("0123456789"), ComputeETag([]byte("0123456789")), nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			// The download breaks off after 4 of 10 bytes.
+			content.ServeHTTP(&truncatingWriter{ResponseWriter: w, limit: 4}, r)
+		case "PUT":
+			if _, err := io.ReadAll(r.Body); err != nil {
+				return
+			}
+			stored.Store(true)
+			w.Write([]byte(`{"key":"dst-key","size":10}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.CopyObject("src-bucket", "src-key", "dst-bucket", "dst-key")
+	assert.ErrorIs(t, err, ErrIncompleteUpload)
+	assert.False(t, stored.Load())
+}
+
+func TestCopyObjectSourceHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "HEAD":
+			assert.Equal(t, "/buckets/src-bucket/objects/src-key", r.URL.Path)
+			w.Header().Set("ETag", "abc123")
+			w.Header().Set("Content-Length", "5")
+		case "PUT":
+			assert.Equal(t, "/buckets/dst-bucket/objects/dst-key", r.URL.Path)
+			assert.Equal(t, "src-bucket/src-key", r.Header.Get("x-object-copy-source"))
+			assert.Equal(t, `"abc123"`, r.Header.Get("x-object-copy-source-if-match"))
+			json.NewEncoder(w).Encode(ObjectMetadata{Key: "dst-key", Size: 5, ETag: "abc123"})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{Copy: true})
+	result, err := client.CopyObject("src-bucket", "src-key", "dst-bucket", "dst-key")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", result.SourceETag)
+}
+
+func TestTouchObject(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		assert.Equal(t, "/buckets/test-bucket/object-info/test-key", r.URL.Path)
+		switch r.Method {
+		case "GET":
+			json.NewEncoder(w).Encode(ObjectMetadata{
+				Key:          "test-key",
+				ContentType:  optionalString("text/plain"),
+				ETag:         "abc123",
+				LastModified: "2024-01-01T00:00:00Z",
+				Metadata:     map[string]string{"owner": "alice"},
+			})
+		case "PUT":
+			assert.Equal(t, `"abc123"`, r.Header.Get("If-Match"))
+			var req updateObjectMetadataRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			assert.Equal(t, "text/plain", *req.ContentType)
+			assert.Equal(t, map[string]string{"owner": "alice"}, req.Metadata)
+			json.NewEncoder(w).Encode(ObjectMetadata{
+				Key:          "test-key",
+				ETag:         "abc123",
+				LastModified: "2024-06-01T00:00:00Z",
+				Metadata:     req.Metadata,
+			})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.TouchObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06-01T00:00:00Z", obj.LastModified)
+	assert.Equal(t, "abc123", obj.ETag)
+	assert.Equal(t, "alice", obj.Metadata["owner"])
+	// The object's data is never rewritten.
+	assert.Equal(t, []string{
+		"GET /buckets/test-bucket/object-info/test-key",
+		"PUT /buckets/test-bucket/object-info/test-key",
+	}, requests)
+}
+
+func TestCopyObjectIfAbsent(t *testing.T) {
+	server := newMemObjectServer(t)
+	defer server.Close()
+	server.put("src-bucket/src-key", "hello", nil)
+	server.put("dst-bucket/taken", "other", nil)
+
+	guarded := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "PUT" {
+			assert.Equal(t, "*", r.Header.Get("If-None-Match"))
+			if server.get(strings.TrimPrefix(strings.Replace(r.URL.Path, "/objects/", "/", 1), "/buckets/")) != nil {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+		}
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer guarded.Close()
+
+	client := NewClient(guarded.URL)
+	result, err := client.CopyObject("src-bucket", "src-key", "dst-bucket", "free", WithIfAbsent())
+	require.NoError(t, err)
+	assert.Equal(t, "free", result.Object.Key)
+
+	_, err = client.CopyObject("src-bucket", "src-key", "dst-bucket", "taken", WithIfAbsent())
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+	assert.Equal(t, "other", string(server.get("dst-bucket/taken").data))
+}
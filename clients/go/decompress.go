@@ -0,0 +1,74 @@
+package objectstorage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// WithTransparentDecompression controls whether GetObject decodes objects
+// stored with Content-Encoding gzip or deflate. When enabled, ObjectData.Data
+// holds the decoded content, Metadata.Size its length and
+// Metadata.ContentEncoding is empty. When disabled, the default, the stored
+// bytes are returned as-is along with their encoding and stored size.
+//
+// Only when enabled does GetObject advertise gzip and deflate in
+// Accept-Encoding; otherwise it asks for the identity encoding, so that a
+// compressing proxy in front of the server cannot hand callers compressed
+// bytes they never asked for. The HTTP transport's own gzip handling never
+// decides the outcome.
+func WithTransparentDecompression(enabled bool) Option {
+	return func(c *Client) {
+		c.decompress = enabled
+	}
+}
+
+// acceptStoredEncoding sets Accept-Encoding explicitly, which stops the
+// transport from negotiating and silently decoding gzip (discarding
+// Content-Length). The encodings decodeContent handles are only advertised
+// when the client decodes responses.
+func (c *Client) acceptStoredEncoding(req *http.Request) {
+	if c.decompress {
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+		return
+	}
+	acceptIdentity(req)
+}
+
+// acceptIdentity asks for the stored bytes without any content coding, for
+// object reads whose responses are never decoded: ranges, resumed and
+// streamed downloads, copies and transforms. Like acceptStoredEncoding, it
+// keeps the transport from negotiating gzip on its own, which would change
+// the size and bytes the caller sees.
+func acceptIdentity(req *http.Request) {
+	req.Header.Set("Accept-Encoding", "identity")
+}
+
+// decodeContent decodes data according to encoding. It reports false for
+// encodings it does not handle, leaving data untouched.
+func decodeContent(encoding string, data []byte) ([]byte, bool, error) {
+	var r io.ReadCloser
+	var err error
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip", "x-gzip":
+		r, err = gzip.NewReader(bytes.NewReader(data))
+	case "deflate":
+		r, err = zlib.NewReader(bytes.NewReader(data))
+	default:
+		return data, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("objectstorage: decoding %s content: %w", encoding, err)
+	}
+	defer r.Close()
+
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return nil, false, fmt.Errorf("objectstorage: decoding %s content: %w", encoding, err)
+	}
+	return decoded, true, nil
+}
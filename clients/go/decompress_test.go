@@ -0,0 +1,124 @@
+package objectstorage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodedServer(t *testing.T, encoding string, body []byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", encoding)
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.Write(body)
+	}))
+}
+
+func TestGetObjectStoredEncoding(t *testing.T) {
+	plain := bytes.Repeat([]byte("hello "), 100)
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(plain)
+	zw.Close()
+
+	server := encodedServer(t, "gzip", gz.Bytes())
+	defer server.Close()
+
+	obj, err := NewClient(server.URL).GetObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, gz.Bytes(), obj.Data)
+	assert.Equal(t, uint64(gz.Len()), obj.Metadata.Size)
+	assert.Equal(t, "gzip", obj.Metadata.ContentEncoding)
+
+	obj, err = NewClient(server.URL, WithTransparentDecompression(true)).GetObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, plain, obj.Data)
+	assert.Equal(t, uint64(len(plain)), obj.Metadata.Size)
+	assert.Empty(t, obj.Metadata.ContentEncoding)
+}
+
+func TestGetObjectDecompressDeflate(t *testing.T) {
+	plain := []byte("deflated content")
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write(plain)
+	zw.Close()
+
+	server := encodedServer(t, "deflate", buf.Bytes())
+	defer server.Close()
+
+	obj, err := NewClient(server.URL, WithTransparentDecompression(true)).GetObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, plain, obj.Data)
+	assert.Equal(t, uint64(len(plain)), obj.Metadata.Size)
+}
+
+func TestGetObjectDecompressCorrupt(t *testing.T) {
+	server := encodedServer(t, "gzip", []byte("not gzip"))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, WithTransparentDecompression(true)).GetObject("test-bucket", "test-key")
+	assert.Error(t, err)
+}
+
+func TestGetObjectAcceptEncoding(t *testing.T) {
+	var accepted string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accepted = r.Header.Get("Accept-Encoding")
+		w.Write([]byte("data"))
+	}))
+	defer server.Close()
+
+	// Without decompression, compressed responses are not invited.
+	_, err := NewClient(server.URL).GetObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, "identity", accepted)
+
+	_, err = NewClient(server.URL, WithTransparentDecompression(true)).GetObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, "gzip, deflate", accepted)
+}
+
+func TestObjectReadsAcceptIdentity(t *testing.T) {
+	var accepted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			accepted = append(accepted, r.Header.Get("Accept-Encoding"))
+			w.Header().Set("ETag", `"e1"`)
+			w.Write([]byte("data"))
+			return
+		}
+		io.Copy(io.Discard, r.Body)
+		w.Write([]byte(`{"key":"copy"}`))
+	}))
+	defer server.Close()
+
+	// Even with decompression enabled, reads that are not decoded ask for
+	// the stored bytes.
+	client := NewClient(server.URL, WithTransparentDecompression(true))
+	_, err := client.GetObjectRange("test-bucket", "test-key", 0, 1)
+	require.NoError(t, err)
+	_, err = client.GetObjectRanges("test-bucket", "test-key", [][2]int64{{0, 1}, {2, 3}})
+	require.NoError(t, err)
+	require.NoError(t, client.TransformObject("test-bucket", "test-key", "copy", func(r io.Reader, w io.Writer) error {
+		_, err := io.Copy(w, r)
+		return err
+	}))
+	download := client.NewResumableDownload("test-bucket", "test-key", filepath.Join(t.TempDir(), "out"), DownloadCheckpoint{})
+	require.NoError(t, download.Download(context.Background()))
+
+	require.NotEmpty(t, accepted)
+	for _, v := range accepted {
+		assert.Equal(t, "identity", v)
+	}
+}
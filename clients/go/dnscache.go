@@ -0,0 +1,110 @@
+package objectstorage
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// WithDNSCache caches the addresses the server's host name resolves to for
+// ttl, instead of resolving it for every new connection. Connections are
+// attempted to each cached address in turn; if all of them fail, the entry is
+// dropped so the next dial resolves the name again. Lookups and dials honour
+// the request's context. It has no effect if the HTTP client uses a custom
+// RoundTripper.
+//
+// Leave it disabled where DNS changes must take effect immediately, for
+// example for failover.
+func WithDNSCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		t := c.transport()
+		if t == nil {
+			return
+		}
+		c.dnsCache = &dnsCache{
+			ttl:     ttl,
+			now:     c.now,
+			lookup:  net.DefaultResolver.LookupHost,
+			entries: make(map[string]dnsEntry),
+		}
+		dial := t.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+		}
+		t.DialContext = c.dnsCache.wrap(dial)
+	}
+}
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+type dnsCache struct {
+	ttl    time.Duration
+	now    func() time.Time
+	lookup func(ctx context.Context, host string) ([]string, error)
+
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+}
+
+type dnsEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// wrap returns a dial function that resolves host names through the cache
+// before calling dial with an IP address.
+func (d *dnsCache) wrap(dial dialFunc) dialFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil || net.ParseIP(host) != nil {
+			return dial(ctx, network, addr)
+		}
+
+		addrs, err := d.resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var firstErr error
+		for _, ip := range addrs {
+			conn, err := dial(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		d.forget(host)
+		return nil, firstErr
+	}
+}
+
+func (d *dnsCache) resolve(ctx context.Context, host string) ([]string, error) {
+	d.mu.Lock()
+	entry, ok := d.entries[host]
+	d.mu.Unlock()
+	if ok && d.now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	addrs, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.entries[host] = dnsEntry{addrs: addrs, expires: d.now().Add(d.ttl)}
+	return addrs, nil
+}
+
+func (d *dnsCache) forget(host string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.entries, host)
+}
@@ -0,0 +1,80 @@
+package objectstorage
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDNSCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	clock := newFakeClock()
+	client := NewClient("http://storage.test:"+u.Port(), WithDNSCache(time.Minute), WithClock(clock), WithDisableKeepAlives())
+
+	var lookups []string
+	addrs := []string{"127.0.0.1"}
+	client.dnsCache.lookup = func(ctx context.Context, host string) ([]string, error) {
+		lookups = append(lookups, host)
+		return addrs, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := client.HeadObject("test-bucket", "test-key")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"storage.test"}, lookups)
+
+	clock.Advance(time.Minute)
+	_, err = client.HeadObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Len(t, lookups, 2)
+
+	// When every cached address fails, the next dial resolves again
+	// without waiting for the entry to expire.
+	addrs = []string{"127.0.0.2"}
+	clock.Advance(time.Minute)
+	_, err = client.HeadObject("test-bucket", "test-key")
+	assert.ErrorIs(t, err, ErrTransport)
+	addrs = []string{"127.0.0.1"}
+	_, err = client.HeadObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Len(t, lookups, 4)
+}
+
+func TestDNSCacheFailover(t *testing.T) {
+	var dialed []string
+	cache := &dnsCache{
+		ttl:     time.Minute,
+		now:     time.Now,
+		entries: make(map[string]dnsEntry),
+		lookup: func(ctx context.Context, host string) ([]string, error) {
+			return []string{"10.0.0.1", "10.0.0.2"}, nil
+		},
+	}
+	dial := cache.wrap(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+		return nil, errors.New("unreachable")
+	})
+
+	_, err := dial(context.Background(), "tcp", "storage.test:80")
+	assert.EqualError(t, err, "unreachable")
+	assert.Equal(t, []string{"10.0.0.1:80", "10.0.0.2:80"}, dialed)
+	assert.Empty(t, cache.entries)
+
+	// IP addresses are dialed directly.
+	dialed = nil
+	dial(context.Background(), "tcp", "10.0.0.9:80")
+	assert.Equal(t, []string{"10.0.0.9:80"}, dialed)
+}
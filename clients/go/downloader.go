@@ -0,0 +1,193 @@
+package objectstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	defaultDownloadPartSize    = 8 << 20
+	defaultDownloadConcurrency = 4
+)
+
+// DownloadOptions configures a parallel download.
+type DownloadOptions struct {
+	// PartSize is the number of bytes fetched per range request. Defaults to
+	// 8 MiB.
+	PartSize int64
+	// Concurrency is the number of parts fetched at once. Defaults to 4.
+	Concurrency int
+	// Progress, if set, is told the object size once known and the bytes
+	// written as each part completes.
+	Progress *ProgressAggregator
+}
+
+// Downloader fetches large objects as concurrent range requests.
+type Downloader struct {
+	client *Client
+}
+
+func NewDownloader(client *Client) *Downloader {
+	return &Downloader{client: client}
+}
+
+// Download writes the object to w, fetching PartSize-sized ranges
+// concurrently and writing each at its offset. All parts are requested with
+// If-Match on the ETag of the first part, so a concurrent overwrite fails the
+// download instead of mixing versions. If the server does not support range
+// requests, the object is downloaded in a single request. If it does not
+// report the object size, parts are fetched one after another until a short
+// one. Every part after the first must be answered with the requested range;
+// otherwise the download fails rather than writing data at the wrong offset.
+// It returns the number of bytes written.
+func (d *Downloader) Download(ctx context.Context, bucket, key string, w io.WriterAt, opts DownloadOptions) (int64, error) {
+	partSize := opts.PartSize
+	if partSize <= 0 {
+		partSize = defaultDownloadPartSize
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultDownloadConcurrency
+	}
+
+	first, err := d.client.getObjectRange(ctx, "Download", bucket, key, 0, partSize-1)
+	if err != nil {
+		var objErr *Error
+		if errors.As(err, &objErr) && objErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+			// Empty objects have no satisfiable range.
+			return 0, nil
+		}
+		return 0, err
+	}
+	if _, err := w.WriteAt(first.data, 0); err != nil {
+		return 0, err
+	}
+	if !first.partial || first.total >= 0 && first.total <= int64(len(first.data)) {
+		opts.Progress.Expect(int64(len(first.data)))
+		opts.Progress.addAt(int64(len(first.data)), d.client.now())
+		return int64(len(first.data)), nil
+	}
+
+	var opt []RequestOption
+	if etag := first.metadata.ETag; etag != "" {
+		opt = append(opt, WithIfMatch(etag))
+	}
+
+	if first.total < 0 {
+		opts.Progress.addAt(int64(len(first.data)), d.client.now())
+		return d.downloadSequential(ctx, bucket, key, w, partSize, int64(len(first.data)), opts, opt)
+	}
+	opts.Progress.Expect(first.total)
+	opts.Progress.addAt(int64(len(first.data)), d.client.now())
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		written  = int64(len(first.data))
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	offsets := make(chan int64)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for off := range offsets {
+				part, err := d.client.getObjectRange(ctx, "Download", bucket, key, off, off+partSize-1, opt...)
+				if err == nil {
+					err = checkPart(part, off, min(partSize, first.total-off))
+				}
+				if err != nil {
+					fail(err)
+					continue
+				}
+				if _, err := w.WriteAt(part.data, off); err != nil {
+					fail(err)
+					continue
+				}
+				atomic.AddInt64(&written, int64(len(part.data)))
+				opts.Progress.addAt(int64(len(part.data)), d.client.now())
+			}
+		}()
+	}
+
+feed:
+	for off := partSize; off < first.total; off += partSize {
+		select {
+		case offsets <- off:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(offsets)
+	wg.Wait()
+
+	if firstErr != nil {
+		return written, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return written, err
+	}
+	return written, nil
+}
+
+// downloadSequential fetches the rest of an object of unknown size from
+// offset written onwards, one part at a time, until the server returns a
+// short part or reports the range unsatisfiable.
+func (d *Downloader) downloadSequential(ctx context.Context, bucket, key string, w io.WriterAt, partSize, written int64, opts DownloadOptions, opt []RequestOption) (int64, error) {
+	if written < partSize {
+		return written, nil
+	}
+	for {
+		part, err := d.client.getObjectRange(ctx, "Download", bucket, key, written, written+partSize-1, opt...)
+		if err != nil {
+			var objErr *Error
+			if errors.As(err, &objErr) && objErr.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+				return written, nil
+			}
+			return written, err
+		}
+		if err := checkPart(part, written, -1); err != nil {
+			return written, err
+		}
+		if _, err := w.WriteAt(part.data, written); err != nil {
+			return written, err
+		}
+		written += int64(len(part.data))
+		opts.Progress.addAt(int64(len(part.data)), d.client.now())
+		if int64(len(part.data)) < partSize {
+			return written, nil
+		}
+	}
+}
+
+// checkPart verifies that part holds the range starting at off, and if size
+// is not negative that it holds exactly size bytes. A server that ignores
+// the Range header and returns the whole object fails the check, so the
+// object is never written at the wrong offset.
+func checkPart(part *rangeResult, off, size int64) error {
+	if !part.partial {
+		return fmt.Errorf("objectstorage: server ignored the range request at offset %d", off)
+	}
+	if part.start != off {
+		return fmt.Errorf("objectstorage: requested range at offset %d, got offset %d", off, part.start)
+	}
+	if size >= 0 && int64(len(part.data)) != size {
+		return fmt.Errorf("objectstorage: range at offset %d: got %d of %d bytes: %w", off, len(part.data), size, io.ErrUnexpectedEOF)
+	}
+	return nil
+}
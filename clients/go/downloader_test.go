@@ -0,0 +1,149 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memWriterAt is an in-memory io.WriterAt.
+type memWriterAt struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (m *memWriterAt) WriteAt(p []byte, off int64) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if end := int(off) + len(p); end > len(m.buf) {
+		m.buf = append(m.buf, make([]byte, end-len(m.buf))...)
+	}
+	copy(m.buf[off:], p)
+	return len(p), nil
+}
+
+func TestDownloaderParallel(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 105)
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		serveContent(data, `"v1"`, nil).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	var w memWriterAt
+	d := NewDownloader(NewClient(server.URL))
+	n, err := d.Download(context.Background(), "test-bucket", "test-key", &w, DownloadOptions{PartSize: 100, Concurrency: 3})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), n)
+	assert.Equal(t, data, w.buf)
+	assert.Equal(t, int32(11), hits.Load())
+}
+
+func TestDownloaderNoRangeSupport(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 1000)
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write(data)
+	}))
+	defer server.Close()
+
+	var w memWriterAt
+	d := NewDownloader(NewClient(server.URL))
+	n, err := d.Download(context.Background(), "test-bucket", "test-key", &w, DownloadOptions{PartSize: 100})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), n)
+	assert.Equal(t, data, w.buf)
+	assert.Equal(t, 1, hits)
+}
+
+func TestDownloaderObjectChanged(t *testing.T) {
+	data := bytes.Repeat([]byte("x"), 1000)
+	etag := `"v1"`
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		current := etag
+		etag = `"v2"`
+		mu.Unlock()
+		serveContent(data, current, nil).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	var w memWriterAt
+	d := NewDownloader(NewClient(server.URL))
+	_, err := d.Download(context.Background(), "test-bucket", "test-key", &w, DownloadOptions{PartSize: 100, Concurrency: 2})
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+}
+
+// serveUnknownLength answers range requests for data without revealing the
+// object size, as in "Content-Range: bytes 0-99/*".
+func serveUnknownLength(data []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spec := strings.TrimPrefix(r.Header.Get("Range"), "bytes=")
+		first, last, _ := strings.Cut(spec, "-")
+		start, _ := strconv.Atoi(first)
+		end, _ := strconv.Atoi(last)
+		if start >= len(data) {
+			w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		end = min(end, len(data)-1)
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Content-Range", "bytes "+strconv.Itoa(start)+"-"+strconv.Itoa(end)+"/*")
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(data[start : end+1])
+	})
+}
+
+func TestDownloaderUnknownLength(t *testing.T) {
+	for _, size := range []int{50, 100, 250, 300} {
+		data := bytes.Repeat([]byte("0123456789"), size/10)
+		server := httptest.NewServer(serveUnknownLength(data))
+
+		var w memWriterAt
+		d := NewDownloader(NewClient(server.URL))
+		n, err := d.Download(context.Background(), "test-bucket", "test-key", &w, DownloadOptions{PartSize: 100})
+		require.NoError(t, err, "size %d", size)
+		assert.Equal(t, int64(size), n)
+		assert.Equal(t, data, w.buf)
+		server.Close()
+	}
+}
+
+func TestDownloaderRangeIgnoredLater(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	var mu sync.Mutex
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		first := requests == 1
+		mu.Unlock()
+		if !first {
+			// A later part is answered with the whole object.
+			w.Header().Set("ETag", `"v1"`)
+			w.Write(data)
+			return
+		}
+		serveContent(data, `"v1"`, nil).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	var w memWriterAt
+	d := NewDownloader(NewClient(server.URL))
+	_, err := d.Download(context.Background(), "test-bucket", "test-key", &w, DownloadOptions{PartSize: 100, Concurrency: 2})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ignored the range request")
+	assert.LessOrEqual(t, len(w.buf), 1000)
+}
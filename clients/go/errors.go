@@ -0,0 +1,141 @@
+package objectstorage
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ErrNotModified matches errors for conditional requests the server answered
+// with 304 Not Modified.
+var ErrNotModified = errors.New("objectstorage: not modified")
+
+// ErrPreconditionFailed matches errors for conditional requests the server
+// rejected with 412 Precondition Failed.
+var ErrPreconditionFailed = errors.New("objectstorage: precondition failed")
+
+// ErrNotSupported is returned when the server does not implement an optional
+// feature.
+var ErrNotSupported = errors.New("objectstorage: not supported by server")
+
+// ErrClientClosed is returned by calls made after Client.Close.
+var ErrClientClosed = errors.New("objectstorage: client closed")
+
+// ErrRateLimited matches errors for requests the server rejected with
+// 429 Too Many Requests. Use errors.As with *Error to read RetryAfter.
+var ErrRateLimited = errors.New("objectstorage: rate limited")
+
+// ErrTransport matches errors for requests that got no response because the
+// server could not be reached or the connection failed. Errors returned with
+// a status code from the server never match it. Use errors.As with
+// *TransportError to inspect the underlying cause.
+var ErrTransport = errors.New("objectstorage: transport error")
+
+// ErrCircuitOpen is returned without contacting the server while the circuit
+// breaker enabled by WithCircuitBreaker is open.
+var ErrCircuitOpen = errors.New("objectstorage: circuit breaker open")
+
+type Error struct {
+	StatusCode int
+	Message    string
+	// Method and URL identify the request that failed. They are not part of
+	// Error() but are useful for logging.
+	Method string
+	URL    string
+	// RequestID is the server-assigned X-Request-Id of the failed response.
+	// Quote it when reporting problems to the storage operator.
+	RequestID string
+	// RetryAfter is the delay requested by the server through Retry-After,
+	// or zero if none was given.
+	RetryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("object storage error (status %d): %s", e.StatusCode, e.Message)
+}
+
+// Is reports whether e corresponds to one of the package's sentinel errors.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotModified:
+		return e.StatusCode == http.StatusNotModified
+	case ErrPreconditionFailed:
+		return e.StatusCode == http.StatusPreconditionFailed
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// TransportError reports a request that failed before the server answered.
+// Err is the error returned by the HTTP client, usually a *url.Error.
+type TransportError struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+func (e *TransportError) Error() string {
+	return fmt.Sprintf("object storage transport error: %v", e.Err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrTransport.
+func (e *TransportError) Is(target error) bool {
+	return target == ErrTransport
+}
+
+// newTransportError wraps err, returned while sending req. Errors caused by
+// the caller cancelling req's context are returned unchanged, since they say
+// nothing about the server.
+func newTransportError(req *http.Request, err error) error {
+	if req.Context().Err() != nil {
+		return err
+	}
+	return &TransportError{Method: req.Method, URL: req.URL.String(), Err: err}
+}
+
+// isNotFound reports whether err is a 404 Not Found response.
+func isNotFound(err error) bool {
+	var objErr *Error
+	return errors.As(err, &objErr) && objErr.StatusCode == http.StatusNotFound
+}
+
+// newError builds an *Error from an unsuccessful response, using its body as
+// the message. Bodies compressed by a gateway are decoded first.
+func (c *Client) newError(resp *http.Response) *Error {
+	return c.errorFromResponse(resp, string(readErrorBody(resp)))
+}
+
+// readErrorBody reads the body of an unsuccessful response, decoding it if a
+// gateway compressed it.
+func readErrorBody(resp *http.Response) []byte {
+	body, _ := io.ReadAll(resp.Body)
+	if encoding := resp.Header.Get("Content-Encoding"); encoding != "" {
+		if decoded, ok, err := decodeContent(encoding, body); ok && err == nil {
+			body = decoded
+		}
+	}
+	return body
+}
+
+func (c *Client) errorFromResponse(resp *http.Response, message string) *Error {
+	e := &Error{
+		StatusCode: resp.StatusCode,
+		Message:    message,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.URL = resp.Request.URL.String()
+	}
+	if d, ok := parseRetryAfter(resp.Header, c.now()); ok {
+		e.RetryAfter = d
+	}
+	return e
+}
@@ -0,0 +1,120 @@
+package objectstorage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorIncludesRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("Object not found"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL + "/")
+	err := client.DeleteObject("test-bucket", "test-key")
+
+	var objErr *Error
+	require.True(t, errors.As(err, &objErr))
+	assert.Equal(t, "DELETE", objErr.Method)
+	assert.Equal(t, server.URL+"//buckets/test-bucket/objects/test-key", objErr.URL)
+	assert.Equal(t, "object storage error (status 404): Object not found", objErr.Error())
+}
+
+func TestRequestID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-"+r.Method)
+		switch r.Method {
+		case "PUT":
+			w.Write([]byte(`{"key":"test-key"}`))
+		case "HEAD":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.PutObject("test-bucket", "test-key", []byte("data"), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "req-PUT", obj.RequestID)
+
+	obj, err = client.HeadObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, "req-HEAD", obj.RequestID)
+
+	err = client.DeleteObject("test-bucket", "test-key")
+	var objErr *Error
+	require.True(t, errors.As(err, &objErr))
+	assert.Equal(t, "req-DELETE", objErr.RequestID)
+}
+
+func TestTransportError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	client := NewClient(url)
+	err := client.DeleteObject("test-bucket", "test-key")
+	assert.ErrorIs(t, err, ErrTransport)
+	assert.True(t, IsConnectionError(err))
+
+	var transportErr *TransportError
+	require.True(t, errors.As(err, &transportErr))
+	assert.Equal(t, "DELETE", transportErr.Method)
+	assert.Equal(t, url+"/buckets/test-bucket/objects/test-key", transportErr.URL)
+
+	var objErr *Error
+	assert.False(t, errors.As(err, &objErr))
+}
+
+func TestTransportErrorNotForResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	err := client.Ping()
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrTransport)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = client.PingContext(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, ErrTransport)
+}
+
+func TestErrorGzipBody(t *testing.T) {
+	var body bytes.Buffer
+	zw := gzip.NewWriter(&body)
+	zw.Write([]byte("bucket quota exceeded"))
+	zw.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write(body.Bytes())
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.GetObject("test-bucket", "test-key")
+	require.Error(t, err)
+	assert.Equal(t, "object storage error (status 403): bucket quota exceeded", err.Error())
+
+	err = client.DeleteObject("test-bucket", "test-key")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bucket quota exceeded")
+}
@@ -0,0 +1,72 @@
+package objectstorage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"strings"
+)
+
+// ComputeETag returns the ETag the server assigns to an object with the given
+// content, so it can be compared with ETagEqual against HeadObject's ETag to
+// skip redundant uploads.
+//
+// The server derives ETags from the hex SHA-256 of the content, not MD5 as S3
+// does. Objects stored by backends that report their own ETags (for example
+// provider MD5 hashes on GCS) and objects assembled from multipart uploads do
+// not match this hash.
+func ComputeETag(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// ComputeETagReader is ComputeETag for content read from r until EOF.
+func ComputeETagReader(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ETagEqual reports whether two entity tags match using the weak comparison
+// of RFC 7232: a W/ prefix and surrounding quotes are ignored, so W/"abc",
+// "abc" and abc are all equal.
+func ETagEqual(a, b string) bool {
+	a, b = opaqueETag(a), opaqueETag(b)
+	return a != "" && a == b
+}
+
+// opaqueETag strips the weakness indicator and quotes from an entity tag.
+func opaqueETag(etag string) string {
+	etag = strings.TrimSpace(etag)
+	etag = strings.TrimPrefix(etag, "W/")
+	if len(etag) >= 2 && etag[0] == '"' && etag[len(etag)-1] == '"' {
+		etag = etag[1 : len(etag)-1]
+	}
+	return etag
+}
+
+// quoteETag formats etag for use in If-Match and If-None-Match headers.
+func quoteETag(etag string) string {
+	if etag == "*" {
+		return etag
+	}
+	return `"` + opaqueETag(etag) + `"`
+}
+
+// WithExpectedETag sends the ETag the uploaded content is known to have, as
+// x-object-expected-etag, for example one computed with ComputeETag by a build
+// system. A server that already stores identical content at the key answers
+// 304 Not Modified instead of storing it again, and PutObject then returns
+// the existing object's metadata with Skipped set. Servers that do not
+// support the header store the upload as usual.
+//
+// Combine it with WithExpectContinue so the server can answer before the
+// body is transmitted.
+func WithExpectedETag(etag string) RequestOption {
+	return func(o *requestOptions) {
+		o.expectedETag = opaqueETag(etag)
+		o.header.Set("x-object-expected-etag", o.expectedETag)
+	}
+}
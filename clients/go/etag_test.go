@@ -0,0 +1,44 @@
+package objectstorage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestETagEqual(t *testing.T) {
+	assert.True(t, ETagEqual(`"abc"`, `"abc"`))
+	assert.True(t, ETagEqual(`W/"abc"`, `"abc"`))
+	assert.True(t, ETagEqual(`W/"abc"`, `W/"abc"`))
+	assert.True(t, ETagEqual("abc", `"abc"`))
+	assert.False(t, ETagEqual(`"abc"`, `"abd"`))
+	assert.False(t, ETagEqual("", ""))
+}
+
+func TestConditionalHeadersNormalizeETag(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, `"abc"`, r.Header.Get("If-Match"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	for _, etag := range []string{"abc", `"abc"`, `W/"abc"`} {
+		_, err := client.GetObject("test-bucket", "test-key", WithIfMatch(etag))
+		require.NoError(t, err)
+	}
+}
+
+func TestComputeETag(t *testing.T) {
+	const helloETag = "dffd6021bb2bd5b0af676290809ec3a53191dd81c7f70a4b28688a362182986f"
+	assert.Equal(t, helloETag, ComputeETag([]byte("Hello, World!")))
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", ComputeETag(nil))
+
+	etag, err := ComputeETagReader(strings.NewReader("Hello, World!"))
+	require.NoError(t, err)
+	assert.Equal(t, helloETag, etag)
+}
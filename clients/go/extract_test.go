@@ -0,0 +1,94 @@
+package objectstorage
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tarEntry struct {
+	hdr  tar.Header
+	body string
+}
+
+func makeTarGz(t *testing.T, entries []tarEntry) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, e := range entries {
+		hdr := e.hdr
+		hdr.Size = int64(len(e.body))
+		require.NoError(t, tw.WriteHeader(&hdr))
+		_, err := tw.Write([]byte(e.body))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestExtractTarGz(t *testing.T) {
+	archive := makeTarGz(t, []tarEntry{
+		{hdr: tar.Header{Name: "bin/", Typeflag: tar.TypeDir, Mode: 0o550}},
+		{hdr: tar.Header{Name: "bin/run.sh", Typeflag: tar.TypeReg, Mode: 0o755}, body: "#!/bin/sh\n"},
+		{hdr: tar.Header{Name: "config/app.yaml", Typeflag: tar.TypeReg, Mode: 0o600}, body: "port: 80\n"},
+		{hdr: tar.Header{Name: "latest", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}},
+	})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/buckets/test-bucket/objects/release.tar.gz", r.URL.Path)
+		w.Write(archive)
+	}))
+	defer server.Close()
+
+	dest := t.TempDir()
+	// Let TempDir remove the read-only directory.
+	t.Cleanup(func() { os.Chmod(filepath.Join(dest, "bin"), 0o755) })
+	client := NewClient(server.URL)
+	require.NoError(t, client.ExtractTarGz("test-bucket", "release.tar.gz", dest))
+
+	data, err := os.ReadFile(filepath.Join(dest, "bin", "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\n", string(data))
+
+	info, err := os.Stat(filepath.Join(dest, "bin", "run.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o755), info.Mode().Perm())
+	info, err = os.Stat(filepath.Join(dest, "config", "app.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	info, err = os.Stat(filepath.Join(dest, "bin"))
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+	assert.Equal(t, os.FileMode(0o550), info.Mode().Perm())
+
+	_, err = os.Lstat(filepath.Join(dest, "latest"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestExtractTarGzRejectsTraversal(t *testing.T) {
+	for _, name := range []string{"../evil.sh", "a/../../evil.sh", "/etc/evil.sh"} {
+		archive := makeTarGz(t, []tarEntry{
+			{hdr: tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0o644}, body: "x"},
+		})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(archive)
+		}))
+
+		parent := t.TempDir()
+		dest := filepath.Join(parent, "dest")
+		client := NewClient(server.URL)
+		err := client.ExtractTarGz("test-bucket", "release.tar.gz", dest)
+		assert.ErrorContains(t, err, "escapes the destination", name)
+		_, statErr := os.Stat(filepath.Join(parent, "evil.sh"))
+		assert.True(t, os.IsNotExist(statErr), name)
+		server.Close()
+	}
+}
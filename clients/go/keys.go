@@ -0,0 +1,81 @@
+package objectstorage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// KeyEncoding selects how object keys are represented on the wire.
+type KeyEncoding int
+
+const (
+	// KeyEncodingNone sends keys as they are. Keys containing control
+	// characters cannot be used.
+	KeyEncodingNone KeyEncoding = iota
+	// KeyEncodingURL percent-encodes every key path segment and marks requests
+	// with encoding-type=url, so keys may contain arbitrary bytes such as
+	// newlines, tabs or invalid UTF-8. Keys in listings are decoded
+	// accordingly.
+	KeyEncodingURL
+)
+
+// WithKeyEncoding sets how object keys are encoded in requests and decoded in
+// listing responses.
+func WithKeyEncoding(enc KeyEncoding) Option {
+	return func(c *Client) {
+		c.keyEncoding = enc
+	}
+}
+
+func (e KeyEncoding) encode(key string) string {
+	if e != KeyEncodingURL {
+		return key
+	}
+	segments := strings.Split(key, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+func (e KeyEncoding) decode(key string) (string, error) {
+	if e != KeyEncodingURL {
+		return key, nil
+	}
+	return url.PathUnescape(key)
+}
+
+// objectURL returns the URL of key under one of the per-object endpoints
+// ("objects", "object-info" or "public-url"). query is appended as is.
+func (c *Client) objectURL(endpoint, bucket, key, query string) string {
+	u := fmt.Sprintf("%s/%s/%s", c.bucketURL(bucket), endpoint, c.keyEncoding.encode(key))
+	if c.keyEncoding == KeyEncodingURL {
+		if query != "" {
+			query += "&"
+		}
+		query += "encoding-type=url"
+	}
+	if query != "" {
+		u += "?" + query
+	}
+	return u
+}
+
+// objectIDKey is the context key under which newObjectRequest records the
+// object a request addresses.
+type objectIDKey struct{}
+
+// newObjectRequest builds a request for key under one of the per-object
+// endpoints. Requests to the objects and object-info endpoints carry the
+// object's "bucket/key" in their context, since it cannot be read back from
+// the URL reliably: with WithVirtualHostedStyle the bucket is in the host.
+func (c *Client) newObjectRequest(ctx context.Context, method, endpoint, bucket, key, query string, body io.Reader) (*http.Request, error) {
+	if endpoint == "objects" || endpoint == "object-info" {
+		ctx = context.WithValue(ctx, objectIDKey{}, bucket+"/"+key)
+	}
+	return http.NewRequestWithContext(ctx, method, c.objectURL(endpoint, bucket, key, query), body)
+}
@@ -0,0 +1,87 @@
+package objectstorage
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyEncodingURLRoundTrip(t *testing.T) {
+	var mu sync.Mutex
+	stored := make(map[string][]byte)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "url", r.URL.Query().Get("encoding-type"))
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Path == "/buckets/test-bucket/objects" {
+			result := listObjectsResponse{Objects: []ObjectMetadata{}}
+			for key, data := range stored {
+				result.Objects = append(result.Objects, ObjectMetadata{Key: url.PathEscape(key), Size: uint64(len(data))})
+			}
+			json.NewEncoder(w).Encode(result)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/buckets/test-bucket/objects/")
+		switch r.Method {
+		case http.MethodPut:
+			data, _ := io.ReadAll(r.Body)
+			stored[key] = data
+			json.NewEncoder(w).Encode(ObjectMetadata{Key: key, Size: uint64(len(data))})
+		case http.MethodGet:
+			data, ok := stored[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithKeyEncoding(KeyEncodingURL))
+	keys := []string{"line\nbreak", "tab\there", "high\xff\xfebytes", "dir/with space/\x01"}
+	for _, key := range keys {
+		_, err := client.PutObject("test-bucket", key, []byte(key), nil, nil)
+		require.NoError(t, err, "%q", key)
+
+		obj, err := client.GetObject("test-bucket", key)
+		require.NoError(t, err, "%q", key)
+		assert.Equal(t, []byte(key), obj.Data)
+	}
+
+	listed, err := client.ListObjects("test-bucket", nil, nil)
+	require.NoError(t, err)
+	var got []string
+	for _, obj := range listed {
+		got = append(got, obj.Key)
+	}
+	assert.ElementsMatch(t, keys, got)
+}
+
+func TestKeyEncodingNoneLeavesRequestsUnchanged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "acl", r.URL.RawQuery)
+		w.Write([]byte(`{"canned":"private"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{ACL: true})
+	_, err := client.GetObjectACL("test-bucket", "test-key")
+	require.NoError(t, err)
+}
+
+func TestKeyEncodingCombinesQuery(t *testing.T) {
+	client := NewClient("http://example.test", WithKeyEncoding(KeyEncodingURL))
+	assert.Equal(t, "http://example.test/buckets/b/objects/a/b%0A?acl&encoding-type=url", client.objectURL("objects", "b", "a/b\n", "acl"))
+}
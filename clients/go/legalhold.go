@@ -0,0 +1,90 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	legalHoldOn  = "ON"
+	legalHoldOff = "OFF"
+)
+
+type legalHold struct {
+	Status string `json:"status"`
+}
+
+// PutObjectLegalHold places (on) or removes a legal hold on an object. While
+// a hold is in place the object cannot be deleted, regardless of any
+// retention period. It returns ErrNotSupported unless Capabilities has
+// reported ObjectLock support, since a server without it would replace the
+// object with the request body.
+func (c *Client) PutObjectLegalHold(bucket, key string, on bool) error {
+	if err := c.requireConfirmedCapability(supportsObjectLock); err != nil {
+		return err
+	}
+
+	hold := legalHold{Status: legalHoldOff}
+	if on {
+		hold.Status = legalHoldOn
+	}
+	body, err := json.Marshal(hold)
+	if err != nil {
+		return err
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "PUT", "objects", bucket, key, "legal-hold", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do("PutObjectLegalHold", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return c.newError(resp)
+	}
+
+	return nil
+}
+
+// GetObjectLegalHold reports whether a legal hold is in place on an object.
+// It returns ErrNotSupported unless Capabilities has reported ObjectLock
+// support.
+func (c *Client) GetObjectLegalHold(bucket, key string) (bool, error) {
+	if err := c.requireConfirmedCapability(supportsObjectLock); err != nil {
+		return false, err
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "GET", "objects", bucket, key, "legal-hold", nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := c.do("GetObjectLegalHold", req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, c.newError(resp)
+	}
+
+	var hold legalHold
+	if err := json.NewDecoder(resp.Body).Decode(&hold); err != nil {
+		return false, err
+	}
+
+	return hold.Status == legalHoldOn, nil
+}
+
+func supportsObjectLock(s *ServerCapabilities) bool {
+	return s.ObjectLock
+}
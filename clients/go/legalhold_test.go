@@ -0,0 +1,66 @@
+package objectstorage
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectLegalHold(t *testing.T) {
+	status := legalHoldOff
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/buckets/test-bucket/objects/test-key", r.URL.Path)
+		switch {
+		case r.Method == "HEAD":
+			w.Header().Set("x-object-legal-hold", status)
+		case r.URL.RawQuery != "legal-hold":
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		case r.Method == "PUT":
+			var hold legalHold
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&hold))
+			status = hold.Status
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == "GET":
+			json.NewEncoder(w).Encode(legalHold{Status: status})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{ObjectLock: true})
+	on, err := client.GetObjectLegalHold("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.False(t, on)
+
+	require.NoError(t, client.PutObjectLegalHold("test-bucket", "test-key", true))
+	on, err = client.GetObjectLegalHold("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.True(t, on)
+
+	obj, err := client.HeadObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.True(t, obj.LegalHold)
+
+	require.NoError(t, client.PutObjectLegalHold("test-bucket", "test-key", false))
+	assert.Equal(t, legalHoldOff, status)
+}
+
+func TestObjectLegalHoldRequiresCapability(t *testing.T) {
+	// memObjectServer ignores ?legal-hold like the real server: a PUT would
+	// replace the object it should protect.
+	server := newMemObjectServer(t)
+	defer server.Close()
+	server.put("test-bucket/test-key", "evidence", nil)
+
+	client := NewClient(server.URL)
+	assert.ErrorIs(t, client.PutObjectLegalHold("test-bucket", "test-key", true), ErrNotSupported)
+	_, err := client.GetObjectLegalHold("test-bucket", "test-key")
+	assert.ErrorIs(t, err, ErrNotSupported)
+
+	assert.Empty(t, server.requests)
+	assert.Equal(t, "evidence", string(server.get("test-bucket/test-key").data))
+}
@@ -0,0 +1,372 @@
+package objectstorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ListResult is a single page of an object listing.
+type ListResult struct {
+	Objects []ObjectMetadata
+	// CommonPrefixes holds the distinct key prefixes up to and including the
+	// first occurrence of the delimiter after the listing prefix, when a
+	// delimiter was given. Objects under those prefixes are not in Objects.
+	CommonPrefixes []string
+	// ETag identifies the state of the listing, if the server reports one.
+	// Pass it to ListObjectsIfChanged to detect changes cheaply.
+	ETag string
+	// IsTruncated reports whether more objects remain after this page, for
+	// example because it was capped by max keys.
+	IsTruncated bool
+	// NextContinuationToken is set when more objects remain after this page.
+	// Pass it to ListObjectsFrom to fetch the next page.
+	NextContinuationToken ContinuationToken
+}
+
+// ContinuationToken is an opaque position in an object listing. It
+// implements encoding.TextMarshaler and encoding.TextUnmarshaler so it can be
+// persisted and used to resume a listing after a restart. Tokens may expire
+// on the server; resuming with an expired token fails with
+// ErrInvalidContinuationToken.
+type ContinuationToken struct {
+	value string
+}
+
+// IsZero reports whether t is empty, meaning there are no further pages.
+func (t ContinuationToken) IsZero() bool {
+	return t.value == ""
+}
+
+func (t ContinuationToken) MarshalText() ([]byte, error) {
+	return []byte(t.value), nil
+}
+
+func (t *ContinuationToken) UnmarshalText(text []byte) error {
+	t.value = string(text)
+	return nil
+}
+
+// ErrInvalidContinuationToken is returned when the server rejects a
+// continuation token, typically because it has expired.
+var ErrInvalidContinuationToken = errors.New("objectstorage: invalid or expired continuation token")
+
+// ErrStopIteration can be returned from a ForEachObject callback to stop
+// iterating without ForEachObject returning an error.
+var ErrStopIteration = errors.New("objectstorage: stop iteration")
+
+// ListOptions selects the objects returned by List. The zero value lists
+// the first page of the whole bucket.
+type ListOptions struct {
+	// Prefix restricts the listing to keys starting with it.
+	Prefix string
+	// Delimiter, if set, rolls up keys containing it after Prefix into
+	// ListResult.CommonPrefixes, like directories.
+	Delimiter string
+	// MaxKeys limits the number of entries per page; zero uses the server
+	// default.
+	MaxKeys int
+	// StartAfter lists only keys that sort after it.
+	StartAfter string
+	// ContinuationToken resumes a previous listing from the page it denotes.
+	ContinuationToken ContinuationToken
+	// MinSize and MaxSize, if set, return only objects whose size in bytes
+	// lies within the inclusive range. They are sent to the server as
+	// min_size and max_size and also applied to each page client-side, for
+	// servers that ignore them, so a page may hold fewer objects than
+	// MaxKeys, or none, while more remain.
+	MinSize *uint64
+	MaxSize *uint64
+}
+
+func (o ListOptions) params() url.Values {
+	params := url.Values{}
+	if o.Prefix != "" {
+		params.Set("prefix", o.Prefix)
+	}
+	if o.Delimiter != "" {
+		params.Set("delimiter", o.Delimiter)
+	}
+	if o.MaxKeys > 0 {
+		params.Set("max_keys", strconv.Itoa(o.MaxKeys))
+	}
+	if o.StartAfter != "" {
+		params.Set("start_after", o.StartAfter)
+	}
+	if !o.ContinuationToken.IsZero() {
+		params.Set("continuation_token", o.ContinuationToken.value)
+	}
+	if o.MinSize != nil {
+		params.Set("min_size", strconv.FormatUint(*o.MinSize, 10))
+	}
+	if o.MaxSize != nil {
+		params.Set("max_size", strconv.FormatUint(*o.MaxSize, 10))
+	}
+	return params
+}
+
+// filterSize removes the objects outside the size range from objects.
+func (o ListOptions) filterSize(objects []ObjectMetadata) []ObjectMetadata {
+	if o.MinSize == nil && o.MaxSize == nil {
+		return objects
+	}
+
+	matching := objects[:0]
+	for _, obj := range objects {
+		if o.MinSize != nil && obj.Size < *o.MinSize {
+			continue
+		}
+		if o.MaxSize != nil && obj.Size > *o.MaxSize {
+			continue
+		}
+		matching = append(matching, obj)
+	}
+	return matching
+}
+
+// List returns one page of objects and common prefixes selected by opts.
+// Pass the returned NextContinuationToken in opts to fetch the next page.
+func (c *Client) List(bucket string, opts ListOptions) (*ListResult, error) {
+	result, err := c.listObjects(context.Background(), "List", bucket, opts.params())
+	if err != nil {
+		return nil, err
+	}
+	result.Objects = opts.filterSize(result.Objects)
+	return result, nil
+}
+
+// ListObjectsIfChanged lists objects like ListObjects, but only if the
+// listing differs from the one identified by etag. If nothing changed, the
+// server answers 304 and ErrNotModified is returned. An empty etag always
+// lists.
+func (c *Client) ListObjectsIfChanged(bucket string, prefix *string, maxKeys *int, etag string) (*ListResult, error) {
+	var opts []RequestOption
+	if etag != "" {
+		opts = append(opts, WithIfNoneMatch(etag))
+	}
+	return c.listObjects(context.Background(), "ListObjectsIfChanged", bucket, listParams(prefix, maxKeys), opts...)
+}
+
+// ListObjectsFrom lists one page of objects starting at token, which comes
+// from a previous ListResult. A zero token starts from the beginning.
+func (c *Client) ListObjectsFrom(bucket string, prefix *string, maxKeys *int, token ContinuationToken) (*ListResult, error) {
+	params := listParams(prefix, maxKeys)
+	if !token.IsZero() {
+		params.Set("continuation_token", token.value)
+	}
+	return c.listObjects(context.Background(), "ListObjectsFrom", bucket, params)
+}
+
+func listParams(prefix *string, maxKeys *int) url.Values {
+	params := url.Values{}
+	if prefix != nil {
+		params.Add("prefix", *prefix)
+	}
+	if maxKeys != nil {
+		params.Add("max_keys", strconv.Itoa(*maxKeys))
+	}
+	return params
+}
+
+// ForEachObject calls fn for every object under prefix, fetching pages as
+// needed. Iteration stops at the first error returned by fn, which is
+// returned unless it is ErrStopIteration. The context is checked between
+// pages.
+//
+// Pages are decoded incrementally, so memory use does not grow with the page
+// size the server chooses.
+func (c *Client) ForEachObject(ctx context.Context, bucket string, prefix *string, fn func(ObjectMetadata) error) error {
+	params := listParams(prefix, nil)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		next, err := c.streamObjects(ctx, "ForEachObject", bucket, params, fn)
+		if err != nil {
+			if errors.Is(err, ErrStopIteration) {
+				return nil
+			}
+			return err
+		}
+
+		if next == "" {
+			return nil
+		}
+		params.Set("continuation_token", next)
+	}
+}
+
+// PrefixSize returns the total size in bytes and the number of objects under
+// prefix, paging through the whole listing.
+func (c *Client) PrefixSize(bucket string, prefix string) (totalBytes uint64, objectCount int, err error) {
+	return c.PrefixSizeContext(context.Background(), bucket, prefix)
+}
+
+// PrefixSizeContext is PrefixSize with a context, which is checked between
+// pages.
+func (c *Client) PrefixSizeContext(ctx context.Context, bucket string, prefix string) (totalBytes uint64, objectCount int, err error) {
+	err = c.ForEachObject(ctx, bucket, &prefix, func(obj ObjectMetadata) error {
+		totalBytes += obj.Size
+		objectCount++
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return totalBytes, objectCount, nil
+}
+
+func (c *Client) listObjects(ctx context.Context, op, bucket string, params url.Values, opts ...RequestOption) (*ListResult, error) {
+	resp, err := c.listRequest(ctx, op, bucket, params, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result listObjectsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	for i := range result.Objects {
+		key, err := c.keyEncoding.decode(result.Objects[i].Key)
+		if err != nil {
+			return nil, err
+		}
+		result.Objects[i].Key = key
+	}
+	for i, prefix := range result.CommonPrefixes {
+		prefix, err := c.keyEncoding.decode(prefix)
+		if err != nil {
+			return nil, err
+		}
+		result.CommonPrefixes[i] = prefix
+	}
+
+	return &ListResult{
+		Objects:               result.Objects,
+		CommonPrefixes:        result.CommonPrefixes,
+		ETag:                  resp.Header.Get("ETag"),
+		IsTruncated:           result.IsTruncated || result.NextContinuationToken != "",
+		NextContinuationToken: ContinuationToken{result.NextContinuationToken},
+	}, nil
+}
+
+// streamObjects fetches one listing page and calls fn for each object as it
+// is decoded, without holding the whole page in memory. It returns the next
+// continuation token, or the first error returned by fn.
+func (c *Client) streamObjects(ctx context.Context, op, bucket string, params url.Values, fn func(ObjectMetadata) error) (string, error) {
+	resp, err := c.listRequest(ctx, op, bucket, params)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	if err := expectDelim(dec, '{'); err != nil {
+		return "", err
+	}
+
+	var next string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", err
+		}
+
+		name, _ := tok.(string)
+		switch canonicalField(listObjectsFields, name) {
+		case "objects":
+			if err := c.streamObjectArray(dec, fn); err != nil {
+				return "", err
+			}
+		case "next_continuation_token":
+			if err := dec.Decode(&next); err != nil {
+				return "", err
+			}
+		default:
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return next, expectDelim(dec, '}')
+}
+
+func (c *Client) streamObjectArray(dec *json.Decoder, fn func(ObjectMetadata) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("objectstorage: unexpected %v in listing objects", tok)
+	}
+
+	for dec.More() {
+		var obj ObjectMetadata
+		if err := dec.Decode(&obj); err != nil {
+			return err
+		}
+		if obj.Key, err = c.keyEncoding.decode(obj.Key); err != nil {
+			return err
+		}
+		if err := fn(obj); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return fmt.Errorf("objectstorage: unexpected %v in listing, want %v", tok, want)
+	}
+	return nil
+}
+
+// listRequest sends a listing request and returns the successful response.
+func (c *Client) listRequest(ctx context.Context, op, bucket string, params url.Values, opts ...RequestOption) (*http.Response, error) {
+	urlPath := c.bucketURL(bucket) + "/objects"
+	if c.keyEncoding == KeyEncodingURL {
+		params.Set("encoding-type", "url")
+	}
+	if len(params) > 0 {
+		urlPath += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", urlPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	newRequestOptions(opts).apply(req)
+
+	resp, err := c.do(op, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		objErr := c.newError(resp)
+		if params.Has("continuation_token") && (resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusGone) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidContinuationToken, objErr)
+		}
+		return nil, objErr
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,298 @@
+package objectstorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListObjectsIfChanged(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/buckets/test-bucket/objects", r.URL.Path)
+		if r.Header.Get("If-None-Match") == `"listing-v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", "listing-v1")
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(listObjectsResponse{
+			Objects: []ObjectMetadata{{Key: "obj1", Size: 100}},
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	result, err := client.ListObjectsIfChanged("test-bucket", nil, nil, "")
+	require.NoError(t, err)
+	assert.Len(t, result.Objects, 1)
+	assert.Equal(t, "listing-v1", result.ETag)
+
+	_, err = client.ListObjectsIfChanged("test-bucket", nil, nil, result.ETag)
+	assert.ErrorIs(t, err, ErrNotModified)
+}
+
+// pagedListing serves keys in pages of pageSize using continuation tokens.
+func pagedListing(t *testing.T, keys []string, pageSize int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/buckets/test-bucket/objects", r.URL.Path)
+
+		start := 0
+		if token := r.URL.Query().Get("continuation_token"); token != "" {
+			fmt.Sscanf(token, "page-%d", &start)
+		}
+		end := start + pageSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		result := listObjectsResponse{Objects: []ObjectMetadata{}}
+		for _, k := range keys[start:end] {
+			result.Objects = append(result.Objects, ObjectMetadata{Key: k, Size: 10})
+		}
+		if end < len(keys) {
+			result.NextContinuationToken = fmt.Sprintf("page-%d", end)
+		}
+		json.NewEncoder(w).Encode(result)
+	})
+}
+
+func TestList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "photos/", q.Get("prefix"))
+		assert.Equal(t, "/", q.Get("delimiter"))
+		assert.Equal(t, "50", q.Get("max_keys"))
+		assert.Equal(t, "photos/a.jpg", q.Get("start_after"))
+		assert.Equal(t, "page-1", q.Get("continuation_token"))
+
+		json.NewEncoder(w).Encode(listObjectsResponse{
+			Objects:               []ObjectMetadata{{Key: "photos/b.jpg", Size: 10}},
+			CommonPrefixes:        []string{"photos/2023/", "photos/2024/"},
+			NextContinuationToken: "page-2",
+		})
+	}))
+	defer server.Close()
+
+	var token ContinuationToken
+	require.NoError(t, token.UnmarshalText([]byte("page-1")))
+
+	client := NewClient(server.URL)
+	result, err := client.List("test-bucket", ListOptions{
+		Prefix:            "photos/",
+		Delimiter:         "/",
+		MaxKeys:           50,
+		StartAfter:        "photos/a.jpg",
+		ContinuationToken: token,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "photos/b.jpg", result.Objects[0].Key)
+	assert.Equal(t, []string{"photos/2023/", "photos/2024/"}, result.CommonPrefixes)
+	assert.False(t, result.NextContinuationToken.IsZero())
+	assert.True(t, result.IsTruncated)
+}
+
+func TestListIsTruncated(t *testing.T) {
+	server := httptest.NewServer(pagedListing(t, []string{"a", "b", "c"}, 2))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	page, err := client.List("test-bucket", ListOptions{})
+	require.NoError(t, err)
+	assert.True(t, page.IsTruncated)
+
+	page, err = client.List("test-bucket", ListOptions{ContinuationToken: page.NextContinuationToken})
+	require.NoError(t, err)
+	assert.Equal(t, "c", page.Objects[0].Key)
+	assert.False(t, page.IsTruncated)
+}
+
+func TestForEachObject(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e"}
+	server := httptest.NewServer(pagedListing(t, keys, 2))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	var seen []string
+	err := client.ForEachObject(context.Background(), "test-bucket", nil, func(obj ObjectMetadata) error {
+		seen = append(seen, obj.Key)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, keys, seen)
+}
+
+func TestForEachObjectStop(t *testing.T) {
+	server := httptest.NewServer(pagedListing(t, []string{"a", "b", "c", "d", "e"}, 2))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	var seen []string
+	err := client.ForEachObject(context.Background(), "test-bucket", nil, func(obj ObjectMetadata) error {
+		seen = append(seen, obj.Key)
+		if obj.Key == "c" {
+			return ErrStopIteration
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, seen)
+
+	boom := errors.New("boom")
+	err = client.ForEachObject(context.Background(), "test-bucket", nil, func(obj ObjectMetadata) error {
+		return boom
+	})
+	assert.ErrorIs(t, err, boom)
+}
+
+func TestForEachObjectContextCanceled(t *testing.T) {
+	server := httptest.NewServer(pagedListing(t, []string{"a", "b", "c", "d", "e"}, 2))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	var seen int
+	err := client.ForEachObject(ctx, "test-bucket", nil, func(obj ObjectMetadata) error {
+		seen++
+		cancel()
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, seen)
+}
+
+func TestForEachObjectStreamsPage(t *testing.T) {
+	// The objects array is followed by fields the stream decoder must skip,
+	// and the callback runs before the rest of the page has been sent.
+	seen := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("continuation_token") == "" {
+			fmt.Fprint(w, `{"objects":[{"key":"a","size":1,"metadata":{"x":"y"}}`)
+			w.(http.Flusher).Flush()
+			assert.Equal(t, "a", <-seen)
+			fmt.Fprint(w, `,{"key":"b","size":2}],"is_truncated":true,"common_prefixes":null,"next_continuation_token":"page-2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"next_continuation_token":"","objects":[{"key":"c","size":3}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	var keys []string
+	err := client.ForEachObject(context.Background(), "test-bucket", nil, func(obj ObjectMetadata) error {
+		keys = append(keys, obj.Key)
+		if obj.Key == "a" {
+			assert.Equal(t, "y", obj.Metadata["x"])
+			seen <- obj.Key
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, keys)
+}
+
+func TestPrefixSize(t *testing.T) {
+	server := httptest.NewServer(pagedListing(t, []string{"a", "b", "c", "d", "e"}, 2))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	total, count, err := client.PrefixSize("test-bucket", "")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(50), total)
+	assert.Equal(t, 5, count)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = client.PrefixSizeContext(ctx, "test-bucket", "")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestListObjectsFromPersistedToken(t *testing.T) {
+	keys := []string{"a", "b", "c", "d", "e"}
+	server := httptest.NewServer(pagedListing(t, keys, 2))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	page, err := client.ListObjectsFrom("test-bucket", nil, nil, ContinuationToken{})
+	require.NoError(t, err)
+	assert.Len(t, page.Objects, 2)
+	require.False(t, page.NextContinuationToken.IsZero())
+
+	saved, err := json.Marshal(struct {
+		Token ContinuationToken `json:"token"`
+	}{page.NextContinuationToken})
+	require.NoError(t, err)
+
+	var restored struct {
+		Token ContinuationToken `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(saved, &restored))
+
+	var seen []string
+	for token := restored.Token; ; {
+		page, err := client.ListObjectsFrom("test-bucket", nil, nil, token)
+		require.NoError(t, err)
+		for _, obj := range page.Objects {
+			seen = append(seen, obj.Key)
+		}
+		if page.NextContinuationToken.IsZero() {
+			break
+		}
+		token = page.NextContinuationToken
+	}
+	assert.Equal(t, []string{"c", "d", "e"}, seen)
+}
+
+func TestListObjectsFromExpiredToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+		w.Write([]byte("continuation token expired"))
+	}))
+	defer server.Close()
+
+	var token ContinuationToken
+	require.NoError(t, token.UnmarshalText([]byte("stale")))
+
+	client := NewClient(server.URL)
+	_, err := client.ListObjectsFrom("test-bucket", nil, nil, token)
+	assert.ErrorIs(t, err, ErrInvalidContinuationToken)
+	assert.Contains(t, err.Error(), "continuation token expired")
+}
+
+func TestListSizeRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		assert.Equal(t, "100", q.Get("min_size"))
+		assert.Equal(t, "1000", q.Get("max_size"))
+
+		// The server ignores the size filters.
+		json.NewEncoder(w).Encode(listObjectsResponse{
+			Objects: []ObjectMetadata{
+				{Key: "small", Size: 99},
+				{Key: "low", Size: 100},
+				{Key: "mid", Size: 500},
+				{Key: "high", Size: 1000},
+				{Key: "large", Size: 1001},
+			},
+		})
+	}))
+	defer server.Close()
+
+	min, max := uint64(100), uint64(1000)
+	client := NewClient(server.URL)
+	result, err := client.List("test-bucket", ListOptions{MinSize: &min, MaxSize: &max})
+	require.NoError(t, err)
+
+	var keys []string
+	for _, obj := range result.Objects {
+		keys = append(keys, obj.Key)
+	}
+	assert.Equal(t, []string{"low", "mid", "high"}, keys)
+}
@@ -0,0 +1,258 @@
+package objectstorage
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DefaultMetadataLimit is the default cap, in bytes, on the combined size of
+// the x-object-meta-* headers of an upload, counting header names and values.
+const DefaultMetadataLimit = 2048
+
+// ErrMetadataTooLarge is returned before an upload is sent when its custom
+// metadata exceeds the client's metadata limit.
+var ErrMetadataTooLarge = errors.New("objectstorage: metadata too large")
+
+// ErrInvalidMetadata is returned before an upload is sent when a metadata key
+// is not a valid header name or a value contains control characters such as
+// newlines.
+var ErrInvalidMetadata = errors.New("objectstorage: invalid metadata")
+
+// WithMetadataLimit sets the maximum combined size of the x-object-meta-*
+// headers of an upload, which defaults to DefaultMetadataLimit. Set it to
+// match the server's limit; a negative limit disables the check.
+func WithMetadataLimit(limit int) Option {
+	return func(c *Client) {
+		c.metadataLimit = limit
+	}
+}
+
+// validateMetadata checks metadata against the client's limit and rejects
+// keys and values that cannot be sent as headers.
+func (c *Client) validateMetadata(metadata map[string]string) error {
+	size := 0
+	for k, v := range metadata {
+		if k == "" || strings.IndexFunc(k, func(r rune) bool { return !isTokenChar(r) }) >= 0 {
+			return fmt.Errorf("%w: key %q is not a valid header name", ErrInvalidMetadata, k)
+		}
+		if strings.IndexFunc(v, func(r rune) bool { return r < ' ' && r != '\t' || r == 0x7f }) >= 0 {
+			return fmt.Errorf("%w: value of %q contains control characters", ErrInvalidMetadata, k)
+		}
+		size += len("x-object-meta-") + len(k) + len(v)
+	}
+
+	limit := c.metadataLimit
+	if limit == 0 {
+		limit = DefaultMetadataLimit
+	}
+	if limit > 0 && size > limit {
+		return fmt.Errorf("%w: %d bytes exceeds limit of %d", ErrMetadataTooLarge, size, limit)
+	}
+	return nil
+}
+
+// isTokenChar reports whether r may appear in an HTTP header name.
+func isTokenChar(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("!#$%&'*+-.^_`|~", r)
+}
+
+// PatchObjectMetadata changes some of an object's custom metadata while
+// keeping the rest: it reads the current metadata, deletes the keys in
+// remove, sets the keys in updates and writes the merged set back. A key in
+// both is set. The content type is kept.
+//
+// The write is conditional on the object's ETag being unchanged since the
+// read, so a concurrent writer is never overwritten; in that case the call
+// fails with ErrPreconditionFailed and can be retried.
+func (c *Client) PatchObjectMetadata(bucket, key string, updates map[string]string, remove []string) (*ObjectMetadata, error) {
+	_, current, err := c.getObjectInfo("PatchObjectMetadata", bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]string, len(current.Metadata)+len(updates))
+	for k, v := range current.Metadata {
+		merged[k] = v
+	}
+	for _, k := range remove {
+		delete(merged, k)
+	}
+	for k, v := range updates {
+		merged[k] = v
+	}
+	if err := c.validateMetadata(merged); err != nil {
+		return nil, err
+	}
+
+	var opts []RequestOption
+	if current.ETag != "" {
+		opts = append(opts, WithIfMatch(current.ETag))
+	}
+	return c.updateObjectMetadata("PatchObjectMetadata", bucket, key, current.ContentType, merged, opts...)
+}
+
+// MarshalMetadata converts a struct into a metadata map for PutObject and
+// related calls, so that a fixed metadata schema can be expressed as a Go
+// type. v must be a struct or a pointer to one.
+//
+// Each exported field becomes one entry, keyed by its objmeta tag or, without
+// one, by the field name. As with encoding/json, a tag of "-" skips the
+// field and an ",omitempty" option skips it when it holds the zero value.
+// Fields may be strings, booleans, integers, floating-point numbers or
+// implement encoding.TextMarshaler, such as time.Time, or be pointers to
+// any of these. Metadata has no null value, so a nil pointer is always
+// skipped.
+func MarshalMetadata(v interface{}) (map[string]string, error) {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("objectstorage: MarshalMetadata of non-struct %T", v)
+	}
+
+	meta := make(map[string]string)
+	for _, f := range metadataFields(rv.Type()) {
+		fv := rv.Field(f.index)
+		if f.omitEmpty && fv.IsZero() || fv.Kind() == reflect.Pointer && fv.IsNil() {
+			continue
+		}
+		s, err := formatMetadataValue(fv)
+		if err != nil {
+			return nil, fmt.Errorf("objectstorage: metadata %q: %w", f.name, err)
+		}
+		meta[f.name] = s
+	}
+	return meta, nil
+}
+
+// UnmarshalMetadata fills the struct v points to from a metadata map, such as
+// ObjectMetadata.Metadata, using the field mapping of MarshalMetadata. Keys
+// match case-insensitively, since metadata read from response headers comes
+// back with canonicalized names. Fields without an entry are left unchanged
+// and entries without a field are ignored. A nil pointer field with an
+// entry is set to a newly allocated value.
+func UnmarshalMetadata(meta map[string]string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("objectstorage: UnmarshalMetadata needs a non-nil struct pointer, got %T", v)
+	}
+	rv = rv.Elem()
+
+	byName := make(map[string]string, len(meta))
+	for k, val := range meta {
+		byName[strings.ToLower(k)] = val
+	}
+	for _, f := range metadataFields(rv.Type()) {
+		s, ok := byName[strings.ToLower(f.name)]
+		if !ok {
+			continue
+		}
+		if err := parseMetadataValue(rv.Field(f.index), s); err != nil {
+			return fmt.Errorf("objectstorage: metadata %q: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+type metadataField struct {
+	index     int
+	name      string
+	omitEmpty bool
+}
+
+func metadataFields(t reflect.Type) []metadataField {
+	var fields []metadataField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(sf.Tag.Get("objmeta"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields = append(fields, metadataField{index: i, name: name, omitEmpty: opts == "omitempty"})
+	}
+	return fields
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// formatMetadataValue formats v, which must not be a nil pointer.
+func formatMetadataValue(v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Pointer {
+		return formatMetadataValue(v.Elem())
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
+
+func parseMetadataValue(v reflect.Value, s string) error {
+	if v.Kind() == reflect.Pointer {
+		// Parse into a new value so that v is only set on success.
+		elem := reflect.New(v.Type().Elem())
+		if err := parseMetadataValue(elem.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+	}
+	if reflect.PointerTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
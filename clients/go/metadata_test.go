@@ -0,0 +1,186 @@
+package objectstorage
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPutObjectMetadataValidation(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{"key":"test-key"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+
+	_, err := client.PutObject("test-bucket", "test-key", []byte("data"), nil, map[string]string{"big": strings.Repeat("x", DefaultMetadataLimit)})
+	assert.ErrorIs(t, err, ErrMetadataTooLarge)
+
+	_, err = client.PutObject("test-bucket", "test-key", []byte("data"), nil, map[string]string{"note": "line\nbreak"})
+	assert.ErrorIs(t, err, ErrInvalidMetadata)
+
+	_, err = client.PutObject("test-bucket", "test-key", []byte("data"), nil, map[string]string{"bad key": "v"})
+	assert.ErrorIs(t, err, ErrInvalidMetadata)
+
+	_, err = client.PutObjectStream("test-bucket", "test-key", strings.NewReader("data"), 4, nil, map[string]string{"note": "a\r\nb"})
+	assert.ErrorIs(t, err, ErrInvalidMetadata)
+	assert.Equal(t, 0, hits)
+
+	_, err = client.PutObject("test-bucket", "test-key", []byte("data"), nil, map[string]string{"note": "tab\tis fine"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, hits)
+}
+
+func TestWithMetadataLimit(t *testing.T) {
+	metadata := map[string]string{"k": strings.Repeat("x", 100)}
+
+	client := NewClient("http://example.test", WithMetadataLimit(64))
+	assert.ErrorIs(t, client.validateMetadata(metadata), ErrMetadataTooLarge)
+
+	client = NewClient("http://example.test", WithMetadataLimit(-1))
+	metadata["k"] = strings.Repeat("x", 10*DefaultMetadataLimit)
+	assert.NoError(t, client.validateMetadata(metadata))
+}
+
+func TestPatchObjectMetadata(t *testing.T) {
+	etag := "v1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/buckets/test-bucket/object-info/doc", r.URL.Path)
+		switch r.Method {
+		case "GET":
+			w.Write([]byte(`{"key":"doc","etag":"` + etag + `","content_type":"text/plain","metadata":{"owner":"ana","stage":"draft","tmp":"1"}}`))
+		case "PUT":
+			if r.Header.Get("If-Match") != `"`+etag+`"` {
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+			var body updateObjectMetadataRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "text/plain", *body.ContentType)
+			assert.Equal(t, map[string]string{"owner": "ana", "stage": "review", "reviewer": "bo"}, body.Metadata)
+			json.NewEncoder(w).Encode(ObjectMetadata{Key: "doc", Metadata: body.Metadata})
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.PatchObjectMetadata("test-bucket", "doc", map[string]string{"stage": "review", "reviewer": "bo"}, []string{"tmp"})
+	require.NoError(t, err)
+	assert.Equal(t, "review", obj.Metadata["stage"])
+	assert.Equal(t, "ana", obj.Metadata["owner"])
+}
+
+func TestPatchObjectMetadataConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Write([]byte(`{"key":"doc","etag":"v1","metadata":{}}`))
+			return
+		}
+		// Another writer changed the object between the read and the write.
+		w.WriteHeader(http.StatusPreconditionFailed)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.PatchObjectMetadata("test-bucket", "doc", map[string]string{"a": "b"}, nil)
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+
+	_, err = client.PatchObjectMetadata("test-bucket", "doc", map[string]string{"bad key": "b"}, nil)
+	assert.ErrorIs(t, err, ErrInvalidMetadata)
+}
+
+type documentMeta struct {
+	UserID   string    `objmeta:"user-id"`
+	Revision int       `objmeta:"revision"`
+	Draft    bool      `objmeta:"draft,omitempty"`
+	Score    float64   `objmeta:"score,omitempty"`
+	Created  time.Time `objmeta:"created"`
+	Internal string    `objmeta:"-"`
+	Region   string
+	secret   string
+}
+
+func TestMarshalMetadata(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	meta, err := MarshalMetadata(documentMeta{
+		UserID:   "u-42",
+		Revision: 3,
+		Created:  created,
+		Internal: "x",
+		Region:   "eu",
+		secret:   "y",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"user-id":  "u-42",
+		"revision": "3",
+		"created":  "2024-01-02T03:04:05Z",
+		"Region":   "eu",
+	}, meta)
+
+	// Metadata read from headers has canonicalized keys.
+	var got documentMeta
+	err = UnmarshalMetadata(map[string]string{
+		"User-Id":  "u-42",
+		"Revision": "3",
+		"Draft":    "true",
+		"Created":  "2024-01-02T03:04:05Z",
+		"Region":   "eu",
+		"Unknown":  "ignored",
+	}, &got)
+	require.NoError(t, err)
+	assert.Equal(t, documentMeta{UserID: "u-42", Revision: 3, Draft: true, Created: created, Region: "eu"}, got)
+}
+
+func TestMetadataPointerFields(t *testing.T) {
+	type pointerMeta struct {
+		Expires *time.Time `objmeta:"expires"`
+		Pages   *int       `objmeta:"pages"`
+		Owner   *string    `objmeta:"owner,omitempty"`
+	}
+
+	// Nil pointers are skipped, with or without omitempty.
+	meta, err := MarshalMetadata(pointerMeta{})
+	require.NoError(t, err)
+	assert.Empty(t, meta)
+
+	expires := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	pages := 12
+	meta, err = MarshalMetadata(&pointerMeta{Expires: &expires, Pages: &pages})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"expires": "2025-06-01T00:00:00Z", "pages": "12"}, meta)
+
+	var got pointerMeta
+	require.NoError(t, UnmarshalMetadata(meta, &got))
+	require.NotNil(t, got.Expires)
+	assert.True(t, expires.Equal(*got.Expires))
+	require.NotNil(t, got.Pages)
+	assert.Equal(t, 12, *got.Pages)
+	assert.Nil(t, got.Owner)
+
+	// A failed parse leaves the field untouched.
+	err = UnmarshalMetadata(map[string]string{"pages": "many"}, &got)
+	assert.Error(t, err)
+	assert.Equal(t, 12, *got.Pages)
+}
+
+func TestUnmarshalMetadataErrors(t *testing.T) {
+	var doc documentMeta
+	err := UnmarshalMetadata(map[string]string{"revision": "three"}, &doc)
+	assert.ErrorContains(t, err, `metadata "revision"`)
+
+	assert.Error(t, UnmarshalMetadata(map[string]string{}, doc))
+	_, err = MarshalMetadata("not a struct")
+	assert.Error(t, err)
+	_, err = MarshalMetadata(struct{ Tags []string }{})
+	assert.ErrorContains(t, err, "unsupported type")
+}
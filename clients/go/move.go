@@ -0,0 +1,113 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+)
+
+// Server support for the atomic move endpoint, as recorded in
+// Client.moveSupport.
+const (
+	moveSupportUnknown int32 = iota
+	moveSupportYes
+	moveSupportNo
+)
+
+type moveObjectRequest struct {
+	Bucket string `json:"bucket"`
+	Key    string `json:"key"`
+}
+
+// MoveResult describes a completed MoveObject.
+type MoveResult struct {
+	// Object is the metadata of the object at its new location.
+	Object ObjectMetadata
+	// Atomic reports whether the server moved the object in a single step.
+	// If false, the move was emulated with CopyObject followed by a delete of
+	// the source, and readers may briefly have seen the object under both
+	// keys.
+	Atomic bool
+}
+
+// MoveObject moves an object to a new bucket and key. It uses the server's
+// atomic move endpoint when available, so readers never observe the object
+// under both or neither key. Servers without it are detected on first use,
+// after which the client falls back to CopyObject followed by a delete of the
+// source, conditional on the ETag that was copied. The source is deleted only
+// if the copy has its size and ETag; otherwise MoveObject fails with an error
+// wrapping ErrCopyMismatch and the source is kept.
+func (c *Client) MoveObject(srcBucket, srcKey, dstBucket, dstKey string) (*MoveResult, error) {
+	if atomic.LoadInt32(&c.moveSupport) != moveSupportNo {
+		obj, err := c.moveObjectAtomic(srcBucket, srcKey, dstBucket, dstKey)
+		if err == nil {
+			atomic.StoreInt32(&c.moveSupport, moveSupportYes)
+			return &MoveResult{Object: *obj, Atomic: true}, nil
+		}
+		if err != ErrNotSupported {
+			return nil, err
+		}
+		atomic.StoreInt32(&c.moveSupport, moveSupportNo)
+	}
+
+	copied, err := c.CopyObject(srcBucket, srcKey, dstBucket, dstKey)
+	if err != nil {
+		return nil, err
+	}
+	// Never delete the source unless the destination holds the same data.
+	if err := verifyCopy(copied.SourceSize, copied.SourceETag, &copied.Object); err != nil {
+		return nil, err
+	}
+
+	var opts []RequestOption
+	if copied.SourceETag != "" {
+		opts = append(opts, WithIfMatch(copied.SourceETag))
+	}
+	if err := c.DeleteObject(srcBucket, srcKey, opts...); err != nil {
+		return nil, err
+	}
+
+	return &MoveResult{Object: copied.Object}, nil
+}
+
+func (c *Client) moveObjectAtomic(srcBucket, srcKey, dstBucket, dstKey string) (*ObjectMetadata, error) {
+	if err := c.requireCapability(func(s *ServerCapabilities) bool { return s.AtomicMove }); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(moveObjectRequest{Bucket: dstBucket, Key: dstKey})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "POST", "objects", srcBucket, srcKey, "move", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do("MoveObject", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		drainBody(resp)
+		return nil, ErrNotSupported
+	default:
+		return nil, c.newError(resp)
+	}
+
+	var objMetadata ObjectMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&objMetadata); err != nil {
+		return nil, err
+	}
+	objMetadata.RequestID = resp.Header.Get("X-Request-Id")
+
+	return &objMetadata, nil
+}
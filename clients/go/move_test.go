@@ -0,0 +1,72 @@
+package objectstorage
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoveObjectAtomic(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path+"?"+r.URL.RawQuery)
+
+		var req moveObjectRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, moveObjectRequest{Bucket: "dst-bucket", Key: "dst-key"}, req)
+		json.NewEncoder(w).Encode(ObjectMetadata{Key: "dst-key", ETag: "abc123"})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	result, err := client.MoveObject("src-bucket", "src-key", "dst-bucket", "dst-key")
+	require.NoError(t, err)
+	assert.True(t, result.Atomic)
+	assert.Equal(t, "dst-key", result.Object.Key)
+	assert.Equal(t, []string{"POST /buckets/src-bucket/objects/src-key?move"}, requests)
+}
+
+func TestMoveObjectFallback(t *testing.T) {
+	server := newMemObjectServer(t)
+	defer server.Close()
+	server.put("src-bucket/src-key", "hello", nil)
+
+	client := NewClient(server.URL)
+	result, err := client.MoveObject("src-bucket", "src-key", "dst-bucket", "dst-key")
+	require.NoError(t, err)
+	assert.False(t, result.Atomic)
+	assert.Equal(t, "dst-key", result.Object.Key)
+	assert.Equal(t, []string{
+		"POST /buckets/src-bucket/objects/src-key",
+		"GET /buckets/src-bucket/objects/src-key",
+		"PUT /buckets/dst-bucket/objects/dst-key",
+		"DELETE /buckets/src-bucket/objects/src-key",
+	}, server.requests)
+	assert.Nil(t, server.get("src-bucket/src-key"))
+	assert.Equal(t, "hello", string(server.get("dst-bucket/dst-key").data))
+
+	// Lack of support is remembered; the endpoint is not probed again.
+	server.put("src-bucket/src-key", "hello", nil)
+	server.requests = nil
+	_, err = client.MoveObject("src-bucket", "src-key", "dst-bucket", "dst-key")
+	require.NoError(t, err)
+	assert.Equal(t, "GET", server.requests[0][:3])
+}
+
+func TestMoveObjectFallbackKeepsSourceOnMismatch(t *testing.T) {
+	// The server claims copy support but stores the empty request body.
+	server := newMemObjectServer(t)
+	defer server.Close()
+	server.put("src-bucket/src-key", "hello", nil)
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{Copy: true})
+	_, err := client.MoveObject("src-bucket", "src-key", "dst-bucket", "dst-key")
+	assert.ErrorIs(t, err, ErrCopyMismatch)
+	assert.Equal(t, "hello", string(server.get("src-bucket/src-key").data))
+	assert.NotContains(t, server.requests, "DELETE /buckets/src-bucket/objects/src-key")
+}
@@ -0,0 +1,295 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MultipartUpload describes a multipart upload that has been started but not
+// yet completed or aborted.
+type MultipartUpload struct {
+	Key      string `json:"key"`
+	UploadID string `json:"upload_id"`
+	// Initiated is when the upload was started, or zero if the server does
+	// not report it.
+	Initiated time.Time `json:"initiated,omitempty"`
+	// PartsCount is the number of parts uploaded so far, or zero if the
+	// server does not report it.
+	PartsCount int `json:"parts_count,omitempty"`
+}
+
+type listMultipartUploadsResponse struct {
+	Uploads               []MultipartUpload `json:"uploads"`
+	NextContinuationToken string            `json:"next_continuation_token,omitempty"`
+}
+
+// ListMultipartUploads returns the in-progress multipart uploads in a bucket
+// whose keys start with prefix, paging through the whole listing.
+func (c *Client) ListMultipartUploads(bucket, prefix string) ([]MultipartUpload, error) {
+	params := url.Values{}
+	if prefix != "" {
+		params.Set("prefix", prefix)
+	}
+	if c.keyEncoding == KeyEncodingURL {
+		params.Set("encoding-type", "url")
+	}
+
+	var uploads []MultipartUpload
+	for {
+		page, err := c.listMultipartUploads(bucket, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range page.Uploads {
+			if u.Key, err = c.keyEncoding.decode(u.Key); err != nil {
+				return nil, err
+			}
+			uploads = append(uploads, u)
+		}
+		if page.NextContinuationToken == "" {
+			return uploads, nil
+		}
+		params.Set("continuation_token", page.NextContinuationToken)
+	}
+}
+
+// ListMultipartUploadsForKey returns the in-progress multipart uploads for
+// exactly key, so that an interrupted upload can be found and resumed.
+func (c *Client) ListMultipartUploadsForKey(bucket, key string) ([]MultipartUpload, error) {
+	uploads, err := c.ListMultipartUploads(bucket, key)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := uploads[:0]
+	for _, u := range uploads {
+		if u.Key == key {
+			matching = append(matching, u)
+		}
+	}
+	return matching, nil
+}
+
+func (c *Client) listMultipartUploads(bucket string, params url.Values) (*listMultipartUploadsResponse, error) {
+	urlPath := c.bucketURL(bucket) + "/uploads"
+	if len(params) > 0 {
+		urlPath += "?" + params.Encode()
+	}
+	req, err := http.NewRequest("GET", urlPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do("ListMultipartUploads", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.newError(resp)
+	}
+
+	var result listMultipartUploadsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CompletedPart identifies an uploaded part in CompleteMultipartUpload.
+type CompletedPart struct {
+	PartNumber int    `json:"part_number"`
+	ETag       string `json:"etag"`
+	// ContentMD5 is the base64-encoded MD5 of the part, which UploadPart sends
+	// as Content-MD5.
+	ContentMD5 string `json:"content_md5,omitempty"`
+	// CRC32C is the part's checksum in the form of ComputeCRC32C, set when it
+	// was uploaded with WithCRC32C.
+	CRC32C string `json:"checksum_crc32c,omitempty"`
+}
+
+type completeMultipartUploadRequest struct {
+	Parts []CompletedPart `json:"parts"`
+}
+
+// CreateMultipartUpload starts a multipart upload of key. The content type
+// and metadata apply to the assembled object.
+func (c *Client) CreateMultipartUpload(bucket, key string, contentType *string, metadata map[string]string, opts ...RequestOption) (*MultipartUpload, error) {
+	if err := c.requireCapability(supportsMultipart); err != nil {
+		return nil, err
+	}
+	if err := c.validateMetadata(metadata); err != nil {
+		return nil, err
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "POST", "objects", bucket, key, "uploads", nil)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != nil {
+		req.Header.Set("Content-Type", *contentType)
+	}
+	setMetadataHeaders(req.Header, metadata)
+	newRequestOptions(opts).apply(req)
+
+	resp, err := c.do("CreateMultipartUpload", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, c.newError(resp)
+	}
+
+	var upload MultipartUpload
+	if err := json.NewDecoder(resp.Body).Decode(&upload); err != nil {
+		return nil, err
+	}
+	upload.Key = key
+	return &upload, nil
+}
+
+// UploadPart uploads one part of a multipart upload. Part numbers start at
+// 1. The part's MD5 is always sent as Content-MD5, and with WithCRC32C its
+// CRC32C is sent as well, so the server can verify the part; a part the
+// server rejects as corrupt fails with an error matching
+// ErrChecksumMismatch. The returned part carries both checksums for
+// CompleteMultipartUpload.
+func (c *Client) UploadPart(bucket, key, uploadID string, partNumber int, data []byte, opts ...RequestOption) (*CompletedPart, error) {
+	if err := c.requireCapability(supportsMultipart); err != nil {
+		return nil, err
+	}
+
+	query := "upload_id=" + url.QueryEscape(uploadID) + "&part_number=" + strconv.Itoa(partNumber)
+	req, err := c.newObjectRequest(context.Background(), "PUT", "objects", bucket, key, query, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	sum := md5.Sum(data)
+	part := &CompletedPart{
+		PartNumber: partNumber,
+		ContentMD5: base64.StdEncoding.EncodeToString(sum[:]),
+	}
+	req.Header.Set("Content-MD5", part.ContentMD5)
+
+	options := newRequestOptions(opts)
+	options.apply(req)
+	if options.sendCRC32C {
+		part.CRC32C = ComputeCRC32C(data)
+		req.Header.Set("x-object-checksum-crc32c", part.CRC32C)
+	}
+
+	resp, err := c.do("UploadPart", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.multipartError(resp)
+	}
+
+	part.ETag = opaqueETag(resp.Header.Get("ETag"))
+	return part, nil
+}
+
+// CompleteMultipartUpload assembles the uploaded parts into the object. The
+// parts, in any order, are sent with their checksums so the server can
+// verify the assembly; if it finds a part does not match, the call fails
+// with an error matching ErrChecksumMismatch.
+//
+// Completion is not idempotent. If an earlier attempt succeeded but its
+// response was lost, a retry fails with a 404 *Error for the upload, and the
+// assembled object cannot be told apart from another write of key: the
+// server's ETag hashes the whole content, which the client never holds. The
+// caller must then check the object against what it uploaded.
+func (c *Client) CompleteMultipartUpload(bucket, key, uploadID string, parts []CompletedPart) (*ObjectMetadata, error) {
+	if err := c.requireCapability(supportsMultipart); err != nil {
+		return nil, err
+	}
+
+	sorted := append([]CompletedPart(nil), parts...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PartNumber < sorted[j].PartNumber })
+	body, err := json.Marshal(completeMultipartUploadRequest{Parts: sorted})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "POST", "objects", bucket, key, "upload_id="+url.QueryEscape(uploadID), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do("CompleteMultipartUpload", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.multipartError(resp)
+	}
+
+	var objMetadata ObjectMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&objMetadata); err != nil {
+		return nil, err
+	}
+	objMetadata.RequestID = resp.Header.Get("X-Request-Id")
+
+	return &objMetadata, nil
+}
+
+// AbortMultipartUpload discards a multipart upload and its uploaded parts.
+func (c *Client) AbortMultipartUpload(bucket, key, uploadID string) error {
+	if err := c.requireCapability(supportsMultipart); err != nil {
+		return err
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "DELETE", "objects", bucket, key, "upload_id="+url.QueryEscape(uploadID), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do("AbortMultipartUpload", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return c.newError(resp)
+	}
+
+	return nil
+}
+
+// multipartError builds the error for an unsuccessful part upload or
+// completion. Servers reject corrupt parts with 422 or, like S3, with 400
+// and a BadDigest or checksum message; those match ErrChecksumMismatch.
+func (c *Client) multipartError(resp *http.Response) error {
+	objErr := c.newError(resp)
+	message := strings.ToLower(objErr.Message)
+	if objErr.StatusCode == http.StatusUnprocessableEntity ||
+		objErr.StatusCode == http.StatusBadRequest && (strings.Contains(message, "baddigest") || strings.Contains(message, "checksum")) {
+		return fmt.Errorf("%w: %w", ErrChecksumMismatch, objErr)
+	}
+	return objErr
+}
+
+func supportsMultipart(s *ServerCapabilities) bool {
+	return s.Multipart
+}
@@ -0,0 +1,163 @@
+package objectstorage
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListMultipartUploadsForKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/buckets/test-bucket/uploads", r.URL.Path)
+		assert.Equal(t, "video.mp4", r.URL.Query().Get("prefix"))
+		if r.URL.Query().Get("continuation_token") == "" {
+			fmt.Fprint(w, `{"uploads":[{"key":"video.mp4","upload_id":"u1","initiated":"2024-01-01T00:00:00Z","parts_count":3},{"key":"video.mp4.bak","upload_id":"u2"}],"next_continuation_token":"page-2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"uploads":[{"Key":"video.mp4","UploadId":"u3"}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	uploads, err := client.ListMultipartUploadsForKey("test-bucket", "video.mp4")
+	require.NoError(t, err)
+	require.Len(t, uploads, 2)
+	assert.Equal(t, "u1", uploads[0].UploadID)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), uploads[0].Initiated)
+	assert.Equal(t, 3, uploads[0].PartsCount)
+	assert.Equal(t, "u3", uploads[1].UploadID)
+	assert.True(t, uploads[1].Initiated.IsZero())
+
+	all, err := client.ListMultipartUploads("test-bucket", "video.mp4")
+	require.NoError(t, err)
+	assert.Len(t, all, 3)
+}
+
+func TestMultipartUploadChecksums(t *testing.T) {
+	parts := map[int][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/buckets/test-bucket/objects/big.bin", r.URL.Path)
+		q := r.URL.Query()
+		switch {
+		case r.Method == "POST" && q.Has("uploads"):
+			assert.Equal(t, "video/mp4", r.Header.Get("Content-Type"))
+			fmt.Fprint(w, `{"upload_id":"u1"}`)
+		case r.Method == "PUT":
+			assert.Equal(t, "u1", q.Get("upload_id"))
+			data, _ := io.ReadAll(r.Body)
+			sum := md5.Sum(data)
+			if r.Header.Get("Content-MD5") != base64.StdEncoding.EncodeToString(sum[:]) {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, "BadDigest: the Content-MD5 you specified did not match")
+				return
+			}
+			if crc := r.Header.Get("x-object-checksum-crc32c"); crc != "" {
+				assert.Equal(t, ComputeCRC32C(data), crc)
+			}
+			n, _ := strconv.Atoi(q.Get("part_number"))
+			parts[n] = data
+			w.Header().Set("ETag", `"`+ComputeETag(data)+`"`)
+		case r.Method == "POST":
+			var body completeMultipartUploadRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			var assembled []byte
+			for i, p := range body.Parts {
+				assert.Equal(t, i+1, p.PartNumber)
+				sum := md5.Sum(parts[p.PartNumber])
+				if p.ContentMD5 != base64.StdEncoding.EncodeToString(sum[:]) {
+					w.WriteHeader(http.StatusUnprocessableEntity)
+					return
+				}
+				assembled = append(assembled, parts[p.PartNumber]...)
+			}
+			json.NewEncoder(w).Encode(ObjectMetadata{Key: "big.bin", Size: uint64(len(assembled))})
+		case r.Method == "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	ct := "video/mp4"
+	upload, err := client.CreateMultipartUpload("test-bucket", "big.bin", &ct, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "u1", upload.UploadID)
+	assert.Equal(t, "big.bin", upload.Key)
+
+	second, err := client.UploadPart("test-bucket", "big.bin", "u1", 2, []byte("world"), WithCRC32C())
+	require.NoError(t, err)
+	assert.Equal(t, ComputeCRC32C([]byte("world")), second.CRC32C)
+	assert.Equal(t, ComputeETag([]byte("world")), second.ETag)
+	first, err := client.UploadPart("test-bucket", "big.bin", "u1", 1, []byte("hello "))
+	require.NoError(t, err)
+	assert.Empty(t, first.CRC32C)
+
+	obj, err := client.CompleteMultipartUpload("test-bucket", "big.bin", "u1", []CompletedPart{*second, *first})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(11), obj.Size)
+
+	// A part that does not match its recorded checksum fails assembly.
+	corrupt := *first
+	corrupt.ContentMD5 = "AAAAAAAAAAAAAAAAAAAAAA=="
+	_, err = client.CompleteMultipartUpload("test-bucket", "big.bin", "u1", []CompletedPart{corrupt, *second})
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+
+	require.NoError(t, client.AbortMultipartUpload("test-bucket", "big.bin", "u1"))
+}
+
+func TestUploadPartChecksumRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, "BadDigest")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.UploadPart("test-bucket", "big.bin", "u1", 1, []byte("data"))
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+	var objErr *Error
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, http.StatusBadRequest, objErr.StatusCode)
+}
+
+func TestCompleteMultipartUploadAfterLostResponse(t *testing.T) {
+	var completions, heads int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "POST":
+			completions++
+			if completions == 1 {
+				// The upload completes but the response is lost.
+				conn, _, err := w.(http.Hijacker).Hijack()
+				require.NoError(t, err)
+				conn.Close()
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "NoSuchUpload")
+		case "HEAD":
+			heads++
+		}
+	}))
+	defer server.Close()
+
+	// The retry's 404 is returned as is; the object is not taken for the
+	// completed upload.
+	client := NewClient(server.URL, WithRetry(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+	_, err := client.CompleteMultipartUpload("test-bucket", "big.bin", "u1", []CompletedPart{{PartNumber: 1, ETag: "p1"}})
+	var objErr *Error
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, http.StatusNotFound, objErr.StatusCode)
+	assert.Equal(t, 2, completions)
+	assert.Zero(t, heads)
+}
@@ -0,0 +1,188 @@
+package objectstorage
+
+import (
+	"net/http"
+	"time"
+)
+
+// Option configures a Client at construction time.
+type Option func(*Client)
+
+// setOptionError records an invalid option. Only the first error is kept.
+func (c *Client) setOptionError(err error) {
+	if c.optionErr == nil {
+		c.optionErr = err
+	}
+}
+
+// WithFaultInjector installs a hook that is consulted before every request.
+// If it returns a non-nil error, the request is not sent and the error is
+// returned as if the transport had failed. op is the client method name
+// (e.g. "PutObject") and attempt starts at 1.
+//
+// This is intended for testing retry and error handling only. Do not use it in
+// production code.
+func WithFaultInjector(fn func(op string, attempt int) error) Option {
+	return func(c *Client) {
+		c.faultInjector = fn
+	}
+}
+
+// WithExpectContinue sends "Expect: 100-continue" on PutObjectStream uploads
+// of at least threshold bytes. The body is only transmitted once the server
+// has accepted the request headers, so uploads rejected up front (for
+// example for authorization or quota reasons) fail without sending the
+// payload. A custom transport must set ExpectContinueTimeout for this to
+// take effect; the default transport does.
+func WithExpectContinue(threshold int64) Option {
+	return func(c *Client) {
+		c.expectContinueThreshold = threshold
+	}
+}
+
+// RequestOption configures a single call.
+type RequestOption func(*requestOptions)
+
+type requestOptions struct {
+	header http.Header
+
+	sendCRC32C     bool
+	verifyCRC32C   bool
+	ignoreNotFound bool
+	expectedETag   string
+	noCoalesce     bool
+}
+
+func newRequestOptions(opts []RequestOption) *requestOptions {
+	o := &requestOptions{header: http.Header{}}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// apply copies the headers collected from the options onto req.
+func (o *requestOptions) apply(req *http.Request) {
+	for k, v := range o.header {
+		req.Header[k] = v
+	}
+}
+
+// WithStorageClass stores an uploaded object in the given storage class
+// (for example "hot", "cold" or "archive"), sent as x-object-storage-class.
+func WithStorageClass(class string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set("x-object-storage-class", class)
+	}
+}
+
+// WithContentLanguage stores a Content-Language with an uploaded object, for
+// example "de-CH" or "en, fr", which is returned as
+// ObjectMetadata.ContentLanguage on reads.
+func WithContentLanguage(language string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set("Content-Language", language)
+	}
+}
+
+// WithContentDisposition stores a Content-Disposition with an uploaded
+// object, for example `attachment; filename="report.pdf"`, so downloads
+// through GetObject or public URLs are saved as files.
+func WithContentDisposition(disposition string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set("Content-Disposition", disposition)
+	}
+}
+
+// WithCacheControl stores a Cache-Control value with an uploaded object,
+// which the server sends back when the object is read so that CDNs and
+// browsers cache it accordingly.
+func WithCacheControl(cacheControl string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set("Cache-Control", cacheControl)
+	}
+}
+
+// WithWebsiteRedirect stores a redirect target with an uploaded object, sent
+// as x-object-website-redirect-location. Requests for the object through the
+// server's website endpoint are answered with a 301 to location, which may be
+// a path in the same bucket, such as "/new/page.html", or an absolute URL.
+func WithWebsiteRedirect(location string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set("x-object-website-redirect-location", location)
+	}
+}
+
+// WithIfMatch makes the request conditional on the object's current ETag
+// matching etag. On mismatch the call fails with ErrPreconditionFailed. etag
+// may be given with or without quotes or a W/ prefix.
+func WithIfMatch(etag string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set("If-Match", quoteETag(etag))
+	}
+}
+
+// WithIfNoneMatch makes the request conditional on the object's current ETag
+// differing from etag. Reads of an unchanged object fail with ErrNotModified.
+func WithIfNoneMatch(etag string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set("If-None-Match", quoteETag(etag))
+	}
+}
+
+// WithIfAbsent makes a write succeed only if no object exists at the target
+// key, by sending "If-None-Match: *". With CopyObject and PutObject, an
+// existing destination makes the call fail with ErrPreconditionFailed instead
+// of being overwritten.
+func WithIfAbsent() RequestOption {
+	return WithIfNoneMatch("*")
+}
+
+// WithIfModifiedSince makes a read conditional on the object having been
+// modified after t. Reads of an unchanged object fail with ErrNotModified.
+func WithIfModifiedSince(t time.Time) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set("If-Modified-Since", t.UTC().Format(http.TimeFormat))
+	}
+}
+
+// WithAccept sends an Accept header so that a server able to convert objects
+// on the fly, for example between image formats, can return the preferred
+// representation. The content type actually returned is reported in
+// ObjectMetadata.ContentType.
+func WithAccept(accept string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set("Accept", accept)
+	}
+}
+
+// WithIgnoreNotFound makes DeleteObject treat a missing object as
+// successfully deleted.
+func WithIgnoreNotFound() RequestOption {
+	return func(o *requestOptions) {
+		o.ignoreNotFound = true
+	}
+}
+
+// WithHeader sets an arbitrary request header, for servers that support
+// extensions this package does not model. When several options set the same
+// header, the last one wins.
+func WithHeader(name, value string) RequestOption {
+	return func(o *requestOptions) {
+		o.header.Set(name, value)
+	}
+}
+
+// Request priorities understood by servers that schedule requests by
+// X-Priority.
+const (
+	PriorityHigh = "high"
+	PriorityLow  = "low"
+)
+
+// WithPriority sends X-Priority so the server can serve latency-sensitive
+// requests ahead of bulk traffic. level is usually PriorityHigh or
+// PriorityLow.
+func WithPriority(level string) RequestOption {
+	return WithHeader("X-Priority", level)
+}
@@ -0,0 +1,72 @@
+package objectstorage
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithFaultInjector(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	injected := errors.New("injected failure")
+	var ops []string
+	client := NewClient(server.URL, WithFaultInjector(func(op string, attempt int) error {
+		ops = append(ops, op)
+		assert.Equal(t, 1, attempt)
+		if op == "DeleteObject" {
+			return injected
+		}
+		return nil
+	}))
+
+	err := client.DeleteObject("test-bucket", "test-key")
+	require.ErrorIs(t, err, injected)
+	assert.Equal(t, 0, hits)
+
+	err = client.DeleteBucket("test-bucket")
+	require.NoError(t, err)
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, []string{"DeleteObject", "DeleteBucket"}, ops)
+}
+
+func TestWithPriority(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "high", r.Header.Get("X-Priority"))
+		assert.Equal(t, "blue", r.Header.Get("X-Pool"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	err := client.DeleteObject("test-bucket", "test-key", WithPriority(PriorityHigh), WithHeader("X-Pool", "blue"))
+	require.NoError(t, err)
+}
+
+func TestWithAccept(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") == "image/webp" {
+			w.Header().Set("Content-Type", "image/webp")
+			w.Write([]byte("webp"))
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("png"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.GetObject("test-bucket", "logo", WithAccept("image/webp"))
+	require.NoError(t, err)
+	assert.Equal(t, "image/webp", *obj.Metadata.ContentType)
+	assert.Equal(t, "webp", string(obj.Data))
+}
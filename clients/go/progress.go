@@ -0,0 +1,97 @@
+package objectstorage
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// ProgressAggregator combines the progress of many concurrent transfers into
+// one total, for example to drive a single progress bar for a directory
+// sync. Pass the same aggregator to several helpers through their options;
+// each registers the bytes it expects to move and reports bytes as they are
+// transferred.
+//
+// ProgressAggregator is safe for concurrent use.
+type ProgressAggregator struct {
+	interval time.Duration
+	fn       func(transferred, expected int64)
+
+	mu          sync.Mutex
+	transferred int64
+	expected    int64
+	lastCall    time.Time
+}
+
+// NewProgressAggregator returns an aggregator that calls fn with the running
+// totals at most once per interval, and always once all expected bytes have
+// been transferred. fn is called with the aggregator locked, so it must not
+// call back into it. A nil fn is allowed; use Progress to poll instead.
+func NewProgressAggregator(interval time.Duration, fn func(transferred, expected int64)) *ProgressAggregator {
+	return &ProgressAggregator{interval: interval, fn: fn}
+}
+
+// Expect adds n bytes to the expected total.
+func (a *ProgressAggregator) Expect(n int64) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.expected += n
+}
+
+// Add records n more transferred bytes.
+func (a *ProgressAggregator) Add(n int64) {
+	a.addAt(n, time.Now())
+}
+
+// addAt is Add at the given time, so that helpers on a Client can use its
+// Clock for the reporting interval.
+func (a *ProgressAggregator) addAt(n int64, now time.Time) {
+	if a == nil || n == 0 {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.transferred += n
+	if a.fn != nil && (a.transferred >= a.expected || now.Sub(a.lastCall) >= a.interval) {
+		a.lastCall = now
+		a.fn(a.transferred, a.expected)
+	}
+}
+
+// Progress returns the bytes transferred and expected so far.
+func (a *ProgressAggregator) Progress() (transferred, expected int64) {
+	if a == nil {
+		return 0, 0
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.transferred, a.expected
+}
+
+// Reader returns a reader that reports the bytes read from r to a.
+func (a *ProgressAggregator) Reader(r io.Reader) io.Reader {
+	return a.reader(r, time.Now)
+}
+
+func (a *ProgressAggregator) reader(r io.Reader, now func() time.Time) io.Reader {
+	if a == nil {
+		return r
+	}
+	return &progressReader{r: r, a: a, now: now}
+}
+
+type progressReader struct {
+	r   io.Reader
+	a   *ProgressAggregator
+	now func() time.Time
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.a.addAt(int64(n), p.now())
+	return n, err
+}
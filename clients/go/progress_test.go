@@ -0,0 +1,79 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressAggregator(t *testing.T) {
+	var calls [][2]int64
+	a := NewProgressAggregator(time.Hour, func(transferred, expected int64) {
+		calls = append(calls, [2]int64{transferred, expected})
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		a.Expect(1000)
+	}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			io.Copy(io.Discard, a.Reader(strings.NewReader(strings.Repeat("x", 1000))))
+		}()
+	}
+	wg.Wait()
+
+	transferred, expected := a.Progress()
+	assert.Equal(t, int64(4000), transferred)
+	assert.Equal(t, int64(4000), expected)
+
+	// The first report is not throttled, later ones are until completion.
+	require.Len(t, calls, 2)
+	assert.Equal(t, [2]int64{4000, 4000}, calls[1])
+}
+
+func TestDownloaderProgress(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 105)
+	server := httptest.NewServer(serveContent(data, `"v1"`, nil))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var last int64
+	progress := NewProgressAggregator(0, func(transferred, expected int64) {
+		mu.Lock()
+		defer mu.Unlock()
+		assert.GreaterOrEqual(t, transferred, last)
+		last = transferred
+	})
+
+	var w memWriterAt
+	d := NewDownloader(NewClient(server.URL))
+	_, err := d.Download(context.Background(), "test-bucket", "test-key", &w, DownloadOptions{PartSize: 100, Concurrency: 3, Progress: progress})
+	require.NoError(t, err)
+
+	transferred, expected := progress.Progress()
+	assert.Equal(t, int64(len(data)), transferred)
+	assert.Equal(t, int64(len(data)), expected)
+	assert.Equal(t, int64(len(data)), last)
+}
+
+func TestProgressAggregatorNil(t *testing.T) {
+	var a *ProgressAggregator
+	a.Expect(10)
+	a.Add(10)
+	transferred, expected := a.Progress()
+	assert.Zero(t, transferred)
+	assert.Zero(t, expected)
+	r := strings.NewReader("data")
+	assert.Same(t, r, a.Reader(r))
+}
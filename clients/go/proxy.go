@@ -0,0 +1,111 @@
+package objectstorage
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// WithProxy sends requests through the HTTP proxy at proxyURL, except for
+// hosts matching one of the noProxy patterns, which are contacted directly.
+// Patterns follow NO_PROXY conventions: "*" matches every host, a domain
+// name matches the domain and its subdomains (a leading "." is optional), an
+// IP address or CIDR range matches by address, and any pattern may carry a
+// ":port" suffix to restrict it to that port.
+//
+// If proxyURL cannot be parsed, every request fails with the parse error. It
+// has no effect if the HTTP client uses a custom RoundTripper.
+func WithProxy(proxyURL string, noProxy ...string) Option {
+	return func(c *Client) {
+		t := c.transport()
+		if t == nil {
+			return
+		}
+
+		u, err := url.Parse(proxyURL)
+		if err == nil && u.Host == "" {
+			err = fmt.Errorf("missing host in proxy URL %q", proxyURL)
+		}
+		if err != nil {
+			t.Proxy = func(*http.Request) (*url.URL, error) {
+				return nil, fmt.Errorf("objectstorage: invalid proxy: %w", err)
+			}
+			return
+		}
+
+		t.Proxy = func(req *http.Request) (*url.URL, error) {
+			if bypassProxy(req.URL, noProxy) {
+				return nil, nil
+			}
+			return u, nil
+		}
+	}
+}
+
+// WithProxyFromEnvironment selects the proxy from the HTTP_PROXY,
+// HTTPS_PROXY and NO_PROXY environment variables, as http.DefaultTransport
+// does. It is useful with NewClientWithHTTP when the given client's transport
+// does not already do so. It has no effect if the HTTP client uses a custom
+// RoundTripper.
+func WithProxyFromEnvironment() Option {
+	return func(c *Client) {
+		if t := c.transport(); t != nil {
+			t.Proxy = http.ProxyFromEnvironment
+		}
+	}
+}
+
+// bypassProxy reports whether u matches one of the noProxy patterns.
+func bypassProxy(u *url.URL, noProxy []string) bool {
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "https":
+			port = "443"
+		default:
+			port = "80"
+		}
+	}
+	ip := net.ParseIP(host)
+
+	for _, pattern := range noProxy {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if pattern == "*" {
+			return true
+		}
+
+		if _, cidr, err := net.ParseCIDR(pattern); err == nil {
+			if ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+
+		patternHost, patternPort := pattern, ""
+		if h, p, err := net.SplitHostPort(pattern); err == nil {
+			patternHost, patternPort = h, p
+		}
+		if patternPort != "" && patternPort != port {
+			continue
+		}
+
+		if patternIP := net.ParseIP(patternHost); patternIP != nil {
+			if ip != nil && patternIP.Equal(ip) {
+				return true
+			}
+			continue
+		}
+
+		domain := strings.TrimPrefix(patternHost, ".")
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
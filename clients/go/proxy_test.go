@@ -0,0 +1,57 @@
+package objectstorage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Requests sent through a proxy carry the absolute target URL.
+		proxied = append(proxied, r.URL.String())
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer proxy.Close()
+
+	client := NewClient("http://storage.example.test", WithProxy(proxy.URL))
+	require.NoError(t, client.Ping())
+	assert.Equal(t, []string{"http://storage.example.test/ping"}, proxied)
+}
+
+func TestWithProxyInvalidURL(t *testing.T) {
+	client := NewClient("http://storage.example.test", WithProxy("://bad"))
+	err := client.Ping()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid proxy")
+}
+
+func TestBypassProxy(t *testing.T) {
+	noProxy := []string{"internal.example", ".corp.test", "10.0.0.0/8", "192.168.1.5", "cache.test:8080"}
+	cases := map[string]bool{
+		"http://internal.example/":      true,
+		"http://api.internal.example/":  true,
+		"http://notinternal.example/":   false,
+		"http://corp.test/":             true,
+		"http://a.b.corp.test/":         true,
+		"http://10.1.2.3/":              true,
+		"http://11.1.2.3/":              false,
+		"http://192.168.1.5:9000/":      true,
+		"http://cache.test:8080/":       true,
+		"http://cache.test/":            false,
+		"https://storage.example.test/": false,
+	}
+	for raw, want := range cases {
+		u, err := url.Parse(raw)
+		require.NoError(t, err)
+		assert.Equal(t, want, bypassProxy(u, noProxy), raw)
+	}
+
+	u, _ := url.Parse("http://anything.test/")
+	assert.True(t, bypassProxy(u, []string{"*"}))
+}
@@ -0,0 +1,352 @@
+package objectstorage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// rangeResult is the outcome of a ranged GET.
+type rangeResult struct {
+	data     []byte
+	metadata ObjectMetadata
+	// partial is false if the server ignored the Range header and returned
+	// the whole object.
+	partial bool
+	// start is the offset of data within the object.
+	start int64
+	// total is the size of the whole object, or -1 if unknown.
+	total int64
+}
+
+// GetObjectRange returns the bytes from start to end (inclusive) of an
+// object. A negative end reads to the end of the object. Metadata.Size is
+// the size of the whole object when the server reports it. If the server
+// does not support ranges, or Capabilities has reported that it lacks them,
+// the whole object is returned.
+func (c *Client) GetObjectRange(bucket, key string, start, end int64, opts ...RequestOption) (*ObjectData, error) {
+	result, err := c.getObjectRange(context.Background(), "GetObjectRange", bucket, key, start, end, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ObjectData{
+		Metadata: result.metadata,
+		Data:     result.data,
+	}, nil
+}
+
+func (c *Client) getObjectRange(ctx context.Context, op, bucket, key string, start, end int64, opts ...RequestOption) (*rangeResult, error) {
+	req, err := c.newObjectRequest(ctx, "GET", "objects", bucket, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	newRequestOptions(opts).apply(req)
+	acceptIdentity(req)
+	if c.requireCapability(supportsRanges) == nil {
+		req.Header.Set("Range", formatRange(start, end))
+	}
+
+	resp, err := c.do(op, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return nil, c.newError(resp)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &rangeResult{
+		data:     data,
+		metadata: metadataFromHeaders(key, resp.Header),
+		total:    int64(len(data)),
+	}
+	if resp.StatusCode == http.StatusPartialContent {
+		result.partial = true
+		result.start, result.total, err = parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return nil, err
+		}
+		if result.total >= 0 {
+			result.metadata.Size = uint64(result.total)
+		}
+	}
+
+	return result, nil
+}
+
+// resumeTruncated completes the body of a GetObject response that ended
+// early, with readErr, after the bytes in data. The rest is requested from
+// where it stopped with Range requests pinned to the response's ETag, so that
+// bytes of different versions are never spliced together; if the object was
+// replaced, the call fails with ErrPreconditionFailed. It makes as many
+// attempts as the retry policy allows beyond the first request, and returns
+// readErr if the body cannot be resumed, for example because the response
+// had no ETag or was compressed.
+func (c *Client) resumeTruncated(bucket, key string, resp *http.Response, data []byte, readErr error) ([]byte, error) {
+	etag := resp.Header.Get("ETag")
+	total := resp.ContentLength
+	if etag == "" || total < 0 || resp.Header.Get("Content-Encoding") != "" || c.requireCapability(supportsRanges) != nil {
+		return nil, readErr
+	}
+
+	for attempt := 1; attempt < c.retry.attempts(); attempt++ {
+		req, err := c.newObjectRequest(context.Background(), "GET", "objects", bucket, key, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("If-Match", quoteETag(etag))
+		req.Header.Set("Range", formatRange(int64(len(data)), -1))
+		acceptIdentity(req)
+
+		resp, err := c.do("GetObject", req)
+		if err != nil {
+			return nil, err
+		}
+
+		switch resp.StatusCode {
+		case http.StatusPartialContent:
+			start, _, err := parseContentRange(resp.Header.Get("Content-Range"))
+			if err != nil {
+				drainBody(resp)
+				return nil, err
+			}
+			if start != int64(len(data)) {
+				drainBody(resp)
+				return nil, fmt.Errorf("objectstorage: resumed download at offset %d, want %d", start, len(data))
+			}
+		case http.StatusOK:
+			// The server ignored the range and sent the whole object again.
+			data = data[:0]
+		default:
+			defer resp.Body.Close()
+			return nil, c.newError(resp)
+		}
+
+		rest, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		data = append(data, rest...)
+		if err == nil && int64(len(data)) >= total {
+			return data, nil
+		}
+		readErr = err
+		if readErr == nil {
+			readErr = io.ErrUnexpectedEOF
+		}
+	}
+
+	return nil, readErr
+}
+
+// RangePart is one of the ranges returned by GetObjectRanges.
+type RangePart struct {
+	// Start is the offset of Data within the object.
+	Start int64
+	Data  []byte
+}
+
+// GetObjectRanges fetches several byte ranges of an object in a single
+// request. Each range is a [start, end] pair with an inclusive end; a
+// negative end reads to the end of the object. Parts are returned in the
+// order of ranges.
+//
+// The server answers with a multipart/byteranges response. If it returns the
+// whole object instead, or omits some of the ranges, the missing ranges are
+// fetched with one request each, pinned to the ETag of the first response.
+// If Capabilities has reported that the server lacks range support, the
+// whole object is fetched once and the ranges are cut from it.
+func (c *Client) GetObjectRanges(bucket, key string, ranges [][2]int64, opts ...RequestOption) ([]RangePart, error) {
+	if len(ranges) == 0 {
+		return nil, nil
+	}
+	if err := c.requireCapability(supportsRanges); err != nil {
+		whole, err := c.getObjectRange(context.Background(), "GetObjectRanges", bucket, key, 0, -1, opts...)
+		if err != nil {
+			return nil, err
+		}
+		rr := receivedRange{total: whole.total, data: whole.data}
+		parts := make([]RangePart, len(ranges))
+		for i, r := range ranges {
+			data, found := rr.slice(r)
+			if !found {
+				return nil, fmt.Errorf("objectstorage: range %s is outside the object", strings.TrimPrefix(formatRange(r[0], r[1]), "bytes="))
+			}
+			parts[i] = RangePart{Start: r[0], Data: data}
+		}
+		return parts, nil
+	}
+
+	specs := make([]string, len(ranges))
+	for i, r := range ranges {
+		specs[i] = strings.TrimPrefix(formatRange(r[0], r[1]), "bytes=")
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "GET", "objects", bucket, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	newRequestOptions(opts).apply(req)
+	acceptIdentity(req)
+	req.Header.Set("Range", "bytes="+strings.Join(specs, ","))
+
+	resp, err := c.do("GetObjectRanges", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var received []receivedRange
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		received, err = readRanges(resp)
+		if err != nil {
+			return nil, err
+		}
+	case http.StatusOK:
+		// Ranges are not supported; don't download the whole object here.
+	default:
+		return nil, c.newError(resp)
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		opts = append(opts, WithIfMatch(etag))
+	}
+
+	parts := make([]RangePart, len(ranges))
+	for i, r := range ranges {
+		var data []byte
+		found := false
+		for _, rr := range received {
+			if data, found = rr.slice(r); found {
+				break
+			}
+		}
+
+		if !found {
+			result, err := c.getObjectRange(context.Background(), "GetObjectRanges", bucket, key, r[0], r[1], opts...)
+			if err != nil {
+				return nil, err
+			}
+			rr := receivedRange{start: result.start, total: result.total, data: result.data}
+			if data, found = rr.slice(r); !found {
+				return nil, fmt.Errorf("objectstorage: server did not return range %s", specs[i])
+			}
+		}
+
+		parts[i] = RangePart{Start: r[0], Data: data}
+	}
+
+	return parts, nil
+}
+
+// receivedRange is a contiguous span of an object received from the server.
+type receivedRange struct {
+	start int64
+	// total is the size of the whole object, or -1 if unknown.
+	total int64
+	data  []byte
+}
+
+// slice returns the bytes of the [start, end] range r if rr covers it. As on
+// the server, an end beyond the object is clipped to its last byte.
+func (rr receivedRange) slice(r [2]int64) ([]byte, bool) {
+	end := r[1]
+	if rr.total >= 0 && (end < 0 || end >= rr.total) {
+		end = rr.total - 1
+	}
+	if end < 0 || r[0] < rr.start || end >= rr.start+int64(len(rr.data)) || r[0] > end+1 {
+		return nil, false
+	}
+	return rr.data[r[0]-rr.start : end-rr.start+1], true
+}
+
+// readRanges reads the spans of a 206 response, which is either a
+// multipart/byteranges body or a single range described by Content-Range.
+func readRanges(resp *http.Response) ([]receivedRange, error) {
+	mediaType, params, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/byteranges" {
+		start, total, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return []receivedRange{{start: start, total: total, data: data}}, nil
+	}
+
+	var received []receivedRange
+	mr := multipart.NewReader(resp.Body, params["boundary"])
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return received, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		start, total, err := parseContentRange(part.Header.Get("Content-Range"))
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, err
+		}
+		received = append(received, receivedRange{start: start, total: total, data: data})
+	}
+}
+
+func supportsRanges(s *ServerCapabilities) bool {
+	return s.Ranges
+}
+
+func formatRange(start, end int64) string {
+	if end < 0 {
+		return fmt.Sprintf("bytes=%d-", start)
+	}
+	return fmt.Sprintf("bytes=%d-%d", start, end)
+}
+
+// parseContentRange parses a "bytes start-end/total" Content-Range value.
+// total is -1 if the server reports it as unknown.
+func parseContentRange(v string) (start, total int64, err error) {
+	spec, ok := strings.CutPrefix(v, "bytes ")
+	if !ok {
+		return 0, 0, fmt.Errorf("objectstorage: invalid Content-Range %q", v)
+	}
+	rng, size, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("objectstorage: invalid Content-Range %q", v)
+	}
+	first, _, ok := strings.Cut(rng, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("objectstorage: invalid Content-Range %q", v)
+	}
+
+	start, err = strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("objectstorage: invalid Content-Range %q", v)
+	}
+	if size == "*" {
+		return start, -1, nil
+	}
+	total, err = strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("objectstorage: invalid Content-Range %q", v)
+	}
+	return start, total, nil
+}
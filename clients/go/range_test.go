@@ -0,0 +1,169 @@
+package objectstorage
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveContent serves data with range and precondition support via
+// http.ServeContent, counting the requests it receives.
+func serveContent(data []byte, etag string, hits *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits != nil {
+			*hits++
+		}
+		w.Header().Set("ETag", etag)
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(data))
+	})
+}
+
+func TestGetObjectRange(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "bytes=7-11", r.Header.Get("Range"))
+		serveContent([]byte("Hello, World!"), `"abc123"`, nil).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.GetObjectRange("test-bucket", "test-key", 7, 11)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("World"), obj.Data)
+	assert.Equal(t, uint64(13), obj.Metadata.Size)
+}
+
+func TestGetObjectRangeUnsupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("Hello, World!"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.GetObjectRange("test-bucket", "test-key", 7, -1)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("Hello, World!"), obj.Data)
+}
+
+func TestParseContentRange(t *testing.T) {
+	start, total, err := parseContentRange("bytes 100-199/1000")
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), start)
+	assert.Equal(t, int64(1000), total)
+
+	_, total, err = parseContentRange("bytes 0-9/*")
+	require.NoError(t, err)
+	assert.Equal(t, int64(-1), total)
+
+	_, _, err = parseContentRange("items 0-9/10")
+	assert.Error(t, err)
+}
+
+func TestGetObjectRanges(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(serveContent([]byte("Hello, World!"), `"abc123"`, &hits))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	parts, err := client.GetObjectRanges("test-bucket", "test-key", [][2]int64{{7, 11}, {0, 4}, {12, -1}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, hits)
+	assert.Equal(t, []RangePart{
+		{Start: 7, Data: []byte("World")},
+		{Start: 0, Data: []byte("Hello")},
+		{Start: 12, Data: []byte("!")},
+	}, parts)
+}
+
+func TestGetObjectRangesFallback(t *testing.T) {
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if strings.Contains(r.Header.Get("Range"), ",") {
+			// Multi-range requests are answered with the whole object.
+			w.Header().Set("ETag", `"abc123"`)
+			w.Write([]byte("Hello, World!"))
+			return
+		}
+		assert.Equal(t, `"abc123"`, r.Header.Get("If-Match"))
+		serveContent([]byte("Hello, World!"), `"abc123"`, nil).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	parts, err := client.GetObjectRanges("test-bucket", "test-key", [][2]int64{{0, 4}, {7, 11}})
+	require.NoError(t, err)
+	assert.Equal(t, []RangePart{
+		{Start: 0, Data: []byte("Hello")},
+		{Start: 7, Data: []byte("World")},
+	}, parts)
+	assert.Equal(t, []string{"bytes=0-4,7-11", "bytes=0-4", "bytes=7-11"}, ranges)
+}
+
+// truncatingWriter drops everything written after limit bytes, so the
+// client sees the connection close before Content-Length bytes arrived.
+type truncatingWriter struct {
+	http.ResponseWriter
+	limit int
+}
+
+func (w *truncatingWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(p) > w.limit {
+		p = p[:w.limit]
+	}
+	w.limit -= len(p)
+	w.ResponseWriter.Write(p)
+	return n, nil
+}
+
+func TestGetObjectResumesTruncatedBody(t *testing.T) {
+	data := []byte("0123456789")
+	var ranges []string
+	content := serveContent(data, `"e1"`, nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if len(ranges) < 3 {
+			w = &truncatingWriter{ResponseWriter: w, limit: 4}
+		}
+		content.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRetry(RetryPolicy{MaxAttempts: 3}))
+	obj, err := client.GetObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, data, obj.Data)
+	assert.Equal(t, uint64(10), obj.Metadata.Size)
+	assert.Equal(t, []string{"", "bytes=4-", "bytes=8-"}, ranges)
+
+	// Without retries the truncation is reported.
+	ranges = nil
+	client = NewClient(server.URL)
+	_, err = client.GetObject("test-bucket", "test-key")
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestGetObjectResumeRejectsReplacedObject(t *testing.T) {
+	first := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if first {
+			first = false
+			serveContent([]byte("0123456789"), `"e1"`, nil).ServeHTTP(&truncatingWriter{ResponseWriter: w, limit: 4}, r)
+			return
+		}
+		// The object was replaced in the meantime.
+		serveContent([]byte("abcdefghij"), `"e2"`, nil).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRetry(RetryPolicy{MaxAttempts: 3}))
+	_, err := client.GetObject("test-bucket", "test-key")
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+}
@@ -0,0 +1,110 @@
+package objectstorage
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimitState holds the most recent rate-limit information reported by
+// the server.
+type rateLimitState struct {
+	mu        sync.Mutex
+	known     bool
+	remaining int
+	resetAt   time.Time
+}
+
+// WithRateLimitThrottling makes the client hold back requests once the server
+// reports that no requests remain in the current window, until the window
+// resets. This avoids running into 429 responses in the first place.
+func WithRateLimitThrottling() Option {
+	return func(c *Client) {
+		c.throttle = true
+	}
+}
+
+// RateLimitStatus returns the rate-limit information from the most recent
+// response that carried it. remaining is -1 if the server has not reported a
+// limit yet; resetAt is zero if no reset time is known.
+func (c *Client) RateLimitStatus() (remaining int, resetAt time.Time) {
+	c.rateLimit.mu.Lock()
+	defer c.rateLimit.mu.Unlock()
+
+	if !c.rateLimit.known {
+		return -1, c.rateLimit.resetAt
+	}
+	return c.rateLimit.remaining, c.rateLimit.resetAt
+}
+
+// observeRateLimit records X-RateLimit-Remaining, X-RateLimit-Reset and
+// Retry-After from resp.
+func (c *Client) observeRateLimit(resp *http.Response) {
+	now := c.now()
+
+	c.rateLimit.mu.Lock()
+	defer c.rateLimit.mu.Unlock()
+
+	if v := resp.Header.Get("X-RateLimit-Remaining"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			c.rateLimit.known = true
+			c.rateLimit.remaining = n
+		}
+	}
+
+	if v := resp.Header.Get("X-RateLimit-Reset"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			// Large values are Unix timestamps, small ones are seconds from now.
+			if n > 1_000_000_000 {
+				c.rateLimit.resetAt = time.Unix(n, 0)
+			} else {
+				c.rateLimit.resetAt = now.Add(time.Duration(n) * time.Second)
+			}
+		}
+	} else if d, ok := parseRetryAfter(resp.Header, now); ok {
+		c.rateLimit.resetAt = now.Add(d)
+	}
+}
+
+// waitForRateLimit blocks until the current rate-limit window resets if
+// throttling is enabled and the server reported no remaining requests.
+func (c *Client) waitForRateLimit(ctx context.Context) error {
+	if !c.throttle {
+		return nil
+	}
+
+	c.rateLimit.mu.Lock()
+	exhausted := c.rateLimit.known && c.rateLimit.remaining <= 0
+	wait := c.rateLimit.resetAt.Sub(c.now())
+	c.rateLimit.mu.Unlock()
+
+	if !exhausted || wait <= 0 {
+		return nil
+	}
+	return c.sleep(ctx, wait)
+}
+
+// parseRetryAfter parses a Retry-After header given either as a number of
+// seconds or as an HTTP date.
+func parseRetryAfter(h http.Header, now time.Time) (time.Duration, bool) {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := t.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
@@ -0,0 +1,60 @@
+package objectstorage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.Header().Set("X-RateLimit-Reset", "1700000000")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	remaining, resetAt := client.RateLimitStatus()
+	assert.Equal(t, -1, remaining)
+	assert.True(t, resetAt.IsZero())
+
+	require.NoError(t, client.Ping())
+	remaining, resetAt = client.RateLimitStatus()
+	assert.Equal(t, 42, remaining)
+	assert.Equal(t, time.Unix(1700000000, 0), resetAt)
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	h := http.Header{}
+	h.Set("Retry-After", "3")
+	d, ok := parseRetryAfter(h, time.Now())
+	require.True(t, ok)
+	assert.Equal(t, 3*time.Second, d)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	h.Set("Retry-After", now.Add(10*time.Second).Format(http.TimeFormat))
+	d, ok = parseRetryAfter(h, now)
+	require.True(t, ok)
+	assert.Equal(t, 10*time.Second, d)
+}
+
+func TestRateLimitThrottling(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRateLimitThrottling())
+	client.rateLimit.known = true
+	client.rateLimit.remaining = 0
+	client.rateLimit.resetAt = time.Now().Add(100 * time.Millisecond)
+
+	start := time.Now()
+	require.NoError(t, client.Ping())
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
@@ -0,0 +1,184 @@
+package objectstorage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+)
+
+// ObjectReaderAt exposes a remote object as an io.ReaderAt. Every ReadAt that
+// is not served from the read-ahead buffer issues one ranged GET. All reads
+// are pinned to the ETag seen when the reader was created, so they fail with
+// ErrPreconditionFailed if the object is replaced.
+//
+// ObjectReaderAt is safe for concurrent use.
+type ObjectReaderAt struct {
+	client *Client
+	bucket string
+	key    string
+	size   int64
+	etag   string
+
+	// ReadAhead is the minimum number of bytes fetched per request. Surplus
+	// bytes are kept and used to serve subsequent reads. Zero disables
+	// read-ahead.
+	ReadAhead int64
+
+	mu       sync.Mutex
+	bufStart int64
+	buf      []byte
+}
+
+// NewObjectReaderAt returns a reader for the object along with its size.
+func (c *Client) NewObjectReaderAt(bucket, key string) (*ObjectReaderAt, int64, error) {
+	meta, err := c.HeadObject(bucket, key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r := &ObjectReaderAt{
+		client: c,
+		bucket: bucket,
+		key:    key,
+		size:   int64(meta.Size),
+		etag:   meta.ETag,
+	}
+	return r, r.size, nil
+}
+
+// Size returns the size of the object.
+func (r *ObjectReaderAt) Size() int64 {
+	return r.size
+}
+
+func (r *ObjectReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("objectstorage: negative offset")
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	want := int64(len(p))
+	if off+want > r.size {
+		want = r.size - off
+	}
+
+	if n, ok := r.fromBuffer(p[:want], off); ok {
+		return r.result(n, len(p))
+	}
+
+	fetch := want
+	if r.ReadAhead > fetch {
+		fetch = r.ReadAhead
+	}
+	if off+fetch > r.size {
+		fetch = r.size - off
+	}
+
+	var opts []RequestOption
+	if r.etag != "" {
+		opts = append(opts, WithIfMatch(r.etag))
+	}
+	part, err := r.client.getObjectRange(context.Background(), "ReadAt", r.bucket, r.key, off, off+fetch-1, opts...)
+	if err != nil {
+		return 0, err
+	}
+
+	data := part.data
+	if !part.partial {
+		// The server returned the whole object; keep only the requested window.
+		if off >= int64(len(data)) {
+			return 0, io.ErrUnexpectedEOF
+		}
+		data = data[off:]
+		if int64(len(data)) > fetch {
+			data = data[:fetch]
+		}
+	}
+
+	n := copy(p[:want], data)
+	if r.ReadAhead > 0 {
+		r.mu.Lock()
+		r.bufStart, r.buf = off, data
+		r.mu.Unlock()
+	}
+	if int64(n) < want {
+		return n, io.ErrUnexpectedEOF
+	}
+	return r.result(n, len(p))
+}
+
+func (r *ObjectReaderAt) fromBuffer(p []byte, off int64) (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if off < r.bufStart || off+int64(len(p)) > r.bufStart+int64(len(r.buf)) {
+		return 0, false
+	}
+	return copy(p, r.buf[off-r.bufStart:]), true
+}
+
+// result reports io.EOF when fewer bytes than requested were available
+// because the read reached the end of the object.
+func (r *ObjectReaderAt) result(n, requested int) (int, error) {
+	if n < requested {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// defaultOpenReadAhead is the read-ahead used by OpenObject, so that small
+// sequential reads do not each cost a request.
+const defaultOpenReadAhead = 1 << 20
+
+var errObjectClosed = errors.New("objectstorage: read from closed object")
+
+// OpenObject presents a remote object as a seekable stream. The size is taken
+// from an initial HEAD and returned alongside; Read lazily issues ranged GETs
+// from the current offset, fetching at least 1 MiB at a time, and Seek only
+// moves the offset, including relative to the end with io.SeekEnd. As with
+// NewObjectReaderAt, reads fail with ErrPreconditionFailed if the object is
+// replaced while open.
+func (c *Client) OpenObject(bucket, key string) (io.ReadSeekCloser, int64, error) {
+	r, size, err := c.NewObjectReaderAt(bucket, key)
+	if err != nil {
+		return nil, 0, err
+	}
+	r.ReadAhead = defaultOpenReadAhead
+	return &objectReadSeeker{r: r, sr: io.NewSectionReader(r, 0, size)}, size, nil
+}
+
+// objectReadSeeker adapts an ObjectReaderAt to io.ReadSeekCloser.
+type objectReadSeeker struct {
+	r      *ObjectReaderAt
+	sr     *io.SectionReader
+	closed bool
+}
+
+func (o *objectReadSeeker) Read(p []byte) (int, error) {
+	if o.closed {
+		return 0, errObjectClosed
+	}
+	return o.sr.Read(p)
+}
+
+func (o *objectReadSeeker) Seek(offset int64, whence int) (int64, error) {
+	if o.closed {
+		return 0, errObjectClosed
+	}
+	return o.sr.Seek(offset, whence)
+}
+
+// Close releases the read-ahead buffer. Further reads fail.
+func (o *objectReadSeeker) Close() error {
+	o.closed = true
+	o.r.mu.Lock()
+	o.r.buf = nil
+	o.r.mu.Unlock()
+	return nil
+}
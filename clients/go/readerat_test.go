@@ -0,0 +1,120 @@
+package objectstorage
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectReaderAt(t *testing.T) {
+	data := []byte("0123456789abcdefghij")
+	var hits int
+	server := httptest.NewServer(serveContent(data, `"v1"`, &hits))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	r, size, err := client.NewObjectReaderAt("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), size)
+
+	p := make([]byte, 5)
+	n, err := r.ReadAt(p, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+	assert.Equal(t, "abcde", string(p))
+
+	n, err = r.ReadAt(p, 18)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "ij", string(p[:n]))
+
+	_, err = r.ReadAt(p, 20)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestObjectReaderAtReadAhead(t *testing.T) {
+	data := []byte("0123456789abcdefghij")
+	var hits int
+	server := httptest.NewServer(serveContent(data, `"v1"`, &hits))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	r, _, err := client.NewObjectReaderAt("test-bucket", "test-key")
+	require.NoError(t, err)
+	r.ReadAhead = 10
+	hits = 0
+
+	p := make([]byte, 2)
+	for off := int64(0); off < 10; off += 2 {
+		_, err := r.ReadAt(p, off)
+		require.NoError(t, err)
+		assert.Equal(t, string(data[off:off+2]), string(p))
+	}
+	assert.Equal(t, 1, hits)
+}
+
+func TestObjectReaderAtZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, err := zw.Create("hello.txt")
+	require.NoError(t, err)
+	f.Write([]byte("Hello, World!"))
+	require.NoError(t, zw.Close())
+
+	server := httptest.NewServer(serveContent(buf.Bytes(), `"zip"`, nil))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	r, size, err := client.NewObjectReaderAt("test-bucket", "archive.zip")
+	require.NoError(t, err)
+
+	zr, err := zip.NewReader(r, size)
+	require.NoError(t, err)
+	require.Len(t, zr.File, 1)
+	rc, err := zr.File[0].Open()
+	require.NoError(t, err)
+	defer rc.Close()
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "Hello, World!", string(content))
+}
+
+func TestOpenObject(t *testing.T) {
+	data := []byte("0123456789abcdefghij")
+	var hits int
+	server := httptest.NewServer(serveContent(data, `"v1"`, &hits))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	r, size, err := client.OpenObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, int64(20), size)
+
+	pos, err := r.Seek(-5, io.SeekEnd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(15), pos)
+	tail, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "fghij", string(tail))
+
+	_, err = r.Seek(0, io.SeekStart)
+	require.NoError(t, err)
+	p := make([]byte, 3)
+	_, err = io.ReadFull(r, p)
+	require.NoError(t, err)
+	assert.Equal(t, "012", string(p))
+	hitsAfterFirstRead := hits
+	_, err = io.ReadFull(r, p)
+	require.NoError(t, err)
+	assert.Equal(t, "345", string(p))
+	assert.Equal(t, hitsAfterFirstRead, hits, "served from read-ahead")
+
+	require.NoError(t, r.Close())
+	_, err = r.Read(p)
+	assert.Error(t, err)
+}
@@ -0,0 +1,96 @@
+package objectstorage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrBucketRegionMismatch matches errors from CreateBucketInRegion when a
+// bucket of that name already exists in another region. Use errors.As with
+// *BucketRegionError to read both regions.
+var ErrBucketRegionMismatch = errors.New("objectstorage: bucket exists in another region")
+
+// BucketRegionError reports that a bucket could not be created in the
+// requested region because it already exists elsewhere.
+type BucketRegionError struct {
+	Bucket          string
+	RequestedRegion string
+	ActualRegion    string
+	// Err is the server's conflict response.
+	Err *Error
+}
+
+func (e *BucketRegionError) Error() string {
+	return fmt.Sprintf("objectstorage: bucket %s requested in region %s already exists in region %s", e.Bucket, e.RequestedRegion, e.ActualRegion)
+}
+
+func (e *BucketRegionError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrBucketRegionMismatch.
+func (e *BucketRegionError) Is(target error) bool {
+	return target == ErrBucketRegionMismatch
+}
+
+// CreateBucketInRegion creates a bucket in the given region. If a bucket of
+// that name already exists in a different region and the server reports
+// which one, through an X-Bucket-Region header or a "region" field in the
+// error body, the error is a *BucketRegionError matching
+// ErrBucketRegionMismatch. Other conflicts are returned as *Error.
+func (c *Client) CreateBucketInRegion(name, region string) (*Bucket, error) {
+	body, err := json.Marshal(createBucketRequest{Name: name, Region: region})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", c.baseURL+"/buckets", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do("CreateBucket", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusConflict:
+		message := readErrorBody(resp)
+		objErr := c.errorFromResponse(resp, string(message))
+		if actual := conflictRegion(resp.Header, message); actual != "" && actual != region {
+			return nil, &BucketRegionError{Bucket: name, RequestedRegion: region, ActualRegion: actual, Err: objErr}
+		}
+		return nil, objErr
+	default:
+		return nil, c.newError(resp)
+	}
+
+	var bucket Bucket
+	if err := json.NewDecoder(resp.Body).Decode(&bucket); err != nil {
+		return nil, err
+	}
+
+	return &bucket, nil
+}
+
+// conflictRegion extracts the existing bucket's region from a conflict
+// response, or returns "" if the server did not report it.
+func conflictRegion(h http.Header, body []byte) string {
+	if region := h.Get("X-Bucket-Region"); region != "" {
+		return region
+	}
+	var payload struct {
+		Region string `json:"region"`
+	}
+	if json.Unmarshal(body, &payload) == nil {
+		return payload.Region
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package objectstorage
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBucketInRegion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req createBucketRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "eu-west-1", req.Region)
+
+		switch req.Name {
+		case "new":
+			json.NewEncoder(w).Encode(Bucket{ID: "1", Name: "new", Region: "eu-west-1"})
+		case "header":
+			w.Header().Set("X-Bucket-Region", "us-east-1")
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("bucket already exists"))
+		case "body":
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":"bucket already exists","region":"ap-south-1"}`))
+		case "same":
+			w.Header().Set("X-Bucket-Region", "eu-west-1")
+			w.WriteHeader(http.StatusConflict)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	bucket, err := client.CreateBucketInRegion("new", "eu-west-1")
+	require.NoError(t, err)
+	assert.Equal(t, "eu-west-1", bucket.Region)
+
+	_, err = client.CreateBucketInRegion("header", "eu-west-1")
+	assert.ErrorIs(t, err, ErrBucketRegionMismatch)
+	var regionErr *BucketRegionError
+	require.True(t, errors.As(err, &regionErr))
+	assert.Equal(t, "eu-west-1", regionErr.RequestedRegion)
+	assert.Equal(t, "us-east-1", regionErr.ActualRegion)
+	var objErr *Error
+	require.True(t, errors.As(err, &objErr))
+	assert.Equal(t, http.StatusConflict, objErr.StatusCode)
+
+	_, err = client.CreateBucketInRegion("body", "eu-west-1")
+	require.True(t, errors.As(err, &regionErr))
+	assert.Equal(t, "ap-south-1", regionErr.ActualRegion)
+
+	_, err = client.CreateBucketInRegion("same", "eu-west-1")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrBucketRegionMismatch)
+}
@@ -0,0 +1,126 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+type renameBucketRequest struct {
+	Name string `json:"name"`
+}
+
+// RenameBucket renames a bucket in place using the server's rename endpoint;
+// no data is moved. If the server does not implement renaming it returns
+// ErrNotSupported, in which case RenameBucketByCopy can be used instead.
+func (c *Client) RenameBucket(oldName, newName string) error {
+	body, err := json.Marshal(renameBucketRequest{Name: newName})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("PATCH", c.baseURL+"/buckets/"+oldName, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do("RenameBucket", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		drainBody(resp)
+		return ErrNotSupported
+	}
+
+	return c.newError(resp)
+}
+
+// RenameOptions configures RenameBucketByCopy.
+type RenameOptions struct {
+	// ContinueOnError keeps going when an object fails to move instead of
+	// stopping at the first failure.
+	ContinueOnError bool
+	// Progress, if set, is called after each object has been handled.
+	Progress func(done, total int, key string, err error)
+}
+
+// RenameReport summarizes a RenameBucketByCopy run.
+type RenameReport struct {
+	Moved  int
+	Failed map[string]error
+	// OldBucketDeleted is true if every object was moved and the old bucket
+	// was removed.
+	OldBucketDeleted bool
+}
+
+// RenameBucketByCopy emulates a bucket rename for servers without a rename
+// endpoint: it creates newName (or reuses it if it exists), copies every
+// object across with CopyObject, and deletes each original once its copy has
+// been verified to have the original's size and ETag. The delete is
+// conditional on that ETag, so an original that changed during the copy is
+// kept. oldName is deleted only if every object was moved. It is not atomic;
+// if it stops early, objects are split between the two buckets and running it
+// again resumes the move.
+func (c *Client) RenameBucketByCopy(oldName, newName string, opts RenameOptions) (*RenameReport, error) {
+	if _, err := c.UpsertBucket(newName); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	err := c.ForEachObject(context.Background(), oldName, nil, func(obj ObjectMetadata) error {
+		keys = append(keys, obj.Key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RenameReport{Failed: make(map[string]error)}
+	for i, key := range keys {
+		err := c.moveVerified(oldName, key, newName)
+
+		if err != nil {
+			report.Failed[key] = err
+		} else {
+			report.Moved++
+		}
+		if opts.Progress != nil {
+			opts.Progress(i+1, len(keys), key, err)
+		}
+		if err != nil && !opts.ContinueOnError {
+			return report, err
+		}
+	}
+
+	if len(report.Failed) > 0 {
+		return report, nil
+	}
+	if err := c.DeleteBucket(oldName); err != nil {
+		return report, err
+	}
+	report.OldBucketDeleted = true
+
+	return report, nil
+}
+
+// moveVerified copies key from oldName to newName and deletes the original
+// if the copy matches it.
+func (c *Client) moveVerified(oldName, key, newName string) error {
+	copied, err := c.CopyObject(oldName, key, newName, key)
+	if err != nil {
+		return err
+	}
+	var opts []RequestOption
+	if copied.SourceETag != "" {
+		opts = append(opts, WithIfMatch(copied.SourceETag))
+	}
+	return c.DeleteObject(oldName, key, append(opts, WithIgnoreNotFound())...)
+}
@@ -0,0 +1,141 @@
+package objectstorage
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameBucket(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PATCH", r.Method)
+		assert.Equal(t, "/buckets/old-bucket", r.URL.Path)
+
+		var req renameBucketRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "new-bucket", req.Name)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	require.NoError(t, client.RenameBucket("old-bucket", "new-bucket"))
+}
+
+func TestRenameBucketNotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	assert.ErrorIs(t, client.RenameBucket("old-bucket", "new-bucket"), ErrNotSupported)
+}
+
+// renameServer serves bucket upserts, deletes and listings, and objects
+// stored from PUT bodies like the real server. Writes to keys in corrupt are
+// stored empty.
+func renameServer(t *testing.T, buckets map[string]map[string]string, corrupt map[string]bool) *httptest.Server {
+	var mu sync.Mutex
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/buckets"), "/", 4)
+		switch {
+		case r.URL.Path == "/buckets" && r.Method == "PUT":
+			var req createBucketRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if buckets[req.Name] == nil {
+				buckets[req.Name] = map[string]string{}
+			}
+			json.NewEncoder(w).Encode(Bucket{Name: req.Name})
+		case len(parts) == 2 && r.Method == "DELETE":
+			delete(buckets, parts[1])
+			w.WriteHeader(http.StatusNoContent)
+		case len(parts) == 3 && r.Method == "GET":
+			result := listObjectsResponse{}
+			for k := range buckets[parts[1]] {
+				result.Objects = append(result.Objects, ObjectMetadata{Key: k})
+			}
+			json.NewEncoder(w).Encode(result)
+		case len(parts) == 4:
+			bucket, key := parts[1], parts[3]
+			content, ok := buckets[bucket][key]
+			switch r.Method {
+			case "GET":
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				w.Header().Set("ETag", ComputeETag([]byte(content)))
+				w.Write([]byte(content))
+			case "PUT":
+				data, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				if corrupt[key] {
+					data = nil
+				}
+				buckets[bucket][key] = string(data)
+				json.NewEncoder(w).Encode(ObjectMetadata{Key: key, Size: uint64(len(data)), ETag: ComputeETag(data)})
+			case "DELETE":
+				assert.Equal(t, quoteETag(ComputeETag([]byte(content))), r.Header.Get("If-Match"))
+				delete(buckets[bucket], key)
+				w.WriteHeader(http.StatusNoContent)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestRenameBucketByCopy(t *testing.T) {
+	buckets := map[string]map[string]string{
+		"old-bucket": {"a.txt": "a", "b.txt": "b"},
+	}
+	server := renameServer(t, buckets, nil)
+	defer server.Close()
+
+	var progress []int
+	client := NewClient(server.URL)
+	report, err := client.RenameBucketByCopy("old-bucket", "new-bucket", RenameOptions{
+		Progress: func(done, total int, key string, err error) {
+			assert.Equal(t, 2, total)
+			assert.NoError(t, err)
+			progress = append(progress, done)
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Moved)
+	assert.Empty(t, report.Failed)
+	assert.True(t, report.OldBucketDeleted)
+	assert.Equal(t, []int{1, 2}, progress)
+
+	assert.NotContains(t, buckets, "old-bucket")
+	assert.Equal(t, map[string]string{"a.txt": "a", "b.txt": "b"}, buckets["new-bucket"])
+}
+
+func TestRenameBucketByCopyMismatch(t *testing.T) {
+	buckets := map[string]map[string]string{
+		"old-bucket": {"a.txt": "a", "b.txt": "b"},
+	}
+	server := renameServer(t, buckets, map[string]bool{"b.txt": true})
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	report, err := client.RenameBucketByCopy("old-bucket", "new-bucket", RenameOptions{ContinueOnError: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Moved)
+	assert.ErrorIs(t, report.Failed["b.txt"], ErrCopyMismatch)
+	assert.False(t, report.OldBucketDeleted)
+
+	// The original of the bad copy and its bucket are kept.
+	assert.Equal(t, map[string]string{"b.txt": "b"}, buckets["old-bucket"])
+}
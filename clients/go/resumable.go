@@ -0,0 +1,194 @@
+package objectstorage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DownloadCheckpoint records the progress of a ResumableDownload. It can be
+// persisted (for example as JSON) and passed to NewResumableDownload to
+// continue after a restart.
+type DownloadCheckpoint struct {
+	// ETag identifies the object version being downloaded.
+	ETag string `json:"etag"`
+	// Received is the number of bytes already written to the local file.
+	Received int64 `json:"received"`
+}
+
+// ResumableDownload downloads an object to a local file across interrupted
+// attempts. Each call to Download continues from the bytes already received
+// with a "Range: bytes=N-" request. If the object has changed since the
+// download started, as detected by its ETag, the file is restarted from
+// scratch so that two versions are never spliced together.
+type ResumableDownload struct {
+	client *Client
+	bucket string
+	key    string
+	path   string
+
+	mu         sync.Mutex
+	checkpoint DownloadCheckpoint
+	total      int64
+}
+
+// NewResumableDownload prepares a download of bucket/key to path, resuming
+// from checkpoint. Pass the zero DownloadCheckpoint to start a new download.
+func (c *Client) NewResumableDownload(bucket, key, path string, checkpoint DownloadCheckpoint) *ResumableDownload {
+	return &ResumableDownload{
+		client:     c,
+		bucket:     bucket,
+		key:        key,
+		path:       path,
+		checkpoint: checkpoint,
+		total:      -1,
+	}
+}
+
+// Checkpoint returns the current progress, suitable for persisting.
+func (d *ResumableDownload) Checkpoint() DownloadCheckpoint {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.checkpoint
+}
+
+// Progress returns the bytes received so far and the size of the object, or
+// -1 if it is not known yet. It may be called while Download is running.
+func (d *ResumableDownload) Progress() (received, total int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.checkpoint.Received, d.total
+}
+
+// Download makes one attempt at fetching the rest of the object. If it fails
+// partway, the bytes received so far are kept and the next call continues
+// from there.
+func (d *ResumableDownload) Download(ctx context.Context) error {
+	f, err := os.OpenFile(d.path, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	cp := d.Checkpoint()
+	if info, err := f.Stat(); err != nil {
+		return err
+	} else if info.Size() < cp.Received {
+		// The local file lost data the checkpoint claims; start over.
+		cp = DownloadCheckpoint{}
+	}
+
+	resp, err := d.request(ctx, cp)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusPartialContent && !ETagEqual(resp.Header.Get("ETag"), cp.ETag) {
+		// The server ignored If-Range and sent a range of a new version.
+		drainBody(resp)
+		cp = DownloadCheckpoint{}
+		if resp, err = d.request(ctx, cp); err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+
+	var total int64 = -1
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		start, size, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return err
+		}
+		if start != cp.Received {
+			return fmt.Errorf("objectstorage: server resumed %s at offset %d, expected %d", d.key, start, cp.Received)
+		}
+		total = size
+	case http.StatusOK:
+		// A fresh download, or the object changed and If-Range made the
+		// server send the new version in full.
+		cp = DownloadCheckpoint{ETag: resp.Header.Get("ETag")}
+		if n, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err == nil {
+			total = n
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Nothing left to fetch, provided the object is unchanged.
+		drainBody(resp)
+		if size, ok := strings.CutPrefix(resp.Header.Get("Content-Range"), "bytes */"); ok && size == strconv.FormatInt(cp.Received, 10) {
+			d.setTotal(cp.Received)
+			return f.Truncate(cp.Received)
+		}
+		d.reset()
+		return fmt.Errorf("objectstorage: cannot resume %s at offset %d: %w", d.key, cp.Received, d.client.newError(resp))
+	default:
+		return d.client.newError(resp)
+	}
+
+	if err := f.Truncate(cp.Received); err != nil {
+		return err
+	}
+	if _, err := f.Seek(cp.Received, io.SeekStart); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.checkpoint = cp
+	d.total = total
+	d.mu.Unlock()
+
+	if _, err := io.Copy(progressWriter{f, d}, resp.Body); err != nil {
+		return err
+	}
+
+	if received, _ := d.Progress(); total >= 0 && received != total {
+		return fmt.Errorf("objectstorage: received %d of %d bytes of %s: %w", received, total, d.key, io.ErrUnexpectedEOF)
+	}
+	return nil
+}
+
+// request fetches the object from the checkpoint onwards. If-Range makes the
+// server return the whole object instead of a range if its ETag changed.
+func (d *ResumableDownload) request(ctx context.Context, cp DownloadCheckpoint) (*http.Response, error) {
+	req, err := d.client.newObjectRequest(ctx, "GET", "objects", d.bucket, d.key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	// Offsets refer to the stored bytes.
+	acceptIdentity(req)
+	if cp.Received > 0 && cp.ETag != "" && d.client.requireCapability(supportsRanges) == nil {
+		req.Header.Set("Range", formatRange(cp.Received, -1))
+		req.Header.Set("If-Range", quoteETag(cp.ETag))
+	}
+	return d.client.do("ResumableDownload", req)
+}
+
+func (d *ResumableDownload) setTotal(total int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.total = total
+}
+
+func (d *ResumableDownload) reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.checkpoint = DownloadCheckpoint{}
+	d.total = -1
+}
+
+// progressWriter writes to w and advances the download's checkpoint.
+type progressWriter struct {
+	w io.Writer
+	d *ResumableDownload
+}
+
+func (p progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.d.mu.Lock()
+	p.d.checkpoint.Received += int64(n)
+	p.d.mu.Unlock()
+	return n, err
+}
@@ -0,0 +1,80 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumableDownload(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if len(ranges) == 1 {
+			// Drop the connection after sending part of the body.
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+			w.Write(data[:300])
+			return
+		}
+		assert.Equal(t, `"v1"`, r.Header.Get("If-Range"))
+		serveContent(data, `"v1"`, nil).ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "object")
+	d := NewClient(server.URL).NewResumableDownload("test-bucket", "test-key", path, DownloadCheckpoint{})
+
+	require.Error(t, d.Download(context.Background()))
+	received, total := d.Progress()
+	assert.Equal(t, int64(300), received)
+	assert.Equal(t, int64(len(data)), total)
+	assert.Equal(t, DownloadCheckpoint{ETag: `"v1"`, Received: 300}, d.Checkpoint())
+
+	require.NoError(t, d.Download(context.Background()))
+	assert.Equal(t, []string{"", "bytes=300-"}, ranges)
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestResumableDownloadRestartsOnChange(t *testing.T) {
+	server := httptest.NewServer(serveContent([]byte("new content"), `"v2"`, nil))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "object")
+	require.NoError(t, os.WriteFile(path, []byte("old co"), 0o644))
+
+	d := NewClient(server.URL).NewResumableDownload("test-bucket", "test-key", path, DownloadCheckpoint{ETag: `"v1"`, Received: 6})
+	require.NoError(t, d.Download(context.Background()))
+	assert.Equal(t, DownloadCheckpoint{ETag: `"v2"`, Received: 11}, d.Checkpoint())
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new content", string(got))
+}
+
+func TestResumableDownloadAlreadyComplete(t *testing.T) {
+	server := httptest.NewServer(serveContent([]byte("done"), `"v1"`, nil))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "object")
+	require.NoError(t, os.WriteFile(path, []byte("done"), 0o644))
+
+	d := NewClient(server.URL).NewResumableDownload("test-bucket", "test-key", path, DownloadCheckpoint{ETag: `"v1"`, Received: 4})
+	require.NoError(t, d.Download(context.Background()))
+
+	got, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "done", string(got))
+}
@@ -0,0 +1,154 @@
+package objectstorage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 5 * time.Second
+)
+
+// RetryPolicy controls how failed requests are retried. Transport errors
+// accepted by ShouldRetryError and 429, 500, 502, 503 and 504 responses are
+// retried; everything else is returned immediately. 429 responses wait for
+// the server's Retry-After. Requests whose body cannot be replayed are never
+// retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// InitialBackoff is the delay before the second attempt. It doubles for
+	// every further attempt. Defaults to 100ms.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts. If the server asks for a
+	// longer wait through Retry-After, the request is not retried and the
+	// error is returned instead. Defaults to 5s.
+	MaxBackoff time.Duration
+	// ShouldRetryError classifies transport errors. Defaults to
+	// DefaultShouldRetryError, which fails fast when the server cannot be
+	// reached at all.
+	ShouldRetryError func(err error) bool
+}
+
+// DefaultShouldRetryError retries timeouts and other transport errors, such
+// as connections reset mid-request, but not connection errors as reported by
+// IsConnectionError: those fail immediately and are unlikely to succeed on a
+// quick retry.
+func DefaultShouldRetryError(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return !IsConnectionError(err)
+}
+
+// IsConnectionError reports whether err means the server could not be
+// reached: the connection was refused or the host name did not resolve.
+func IsConnectionError(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.Is(err, syscall.ECONNREFUSED) || errors.As(err, &dnsErr)
+}
+
+// WithRetry enables retries according to policy.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = &policy
+	}
+}
+
+func (p *RetryPolicy) shouldRetryError(err error) bool {
+	if p.ShouldRetryError != nil {
+		return p.ShouldRetryError(err)
+	}
+	return DefaultShouldRetryError(err)
+}
+
+func (p *RetryPolicy) attempts() int {
+	if p == nil || p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns the delay before the attempt following attempt. A positive
+// retryAfter, as sent by the server, takes precedence over the exponential
+// schedule. It reports false if the server asked for a longer wait than
+// MaxBackoff allows, in which case the request should not be retried.
+func (p *RetryPolicy) backoff(attempt int, retryAfter time.Duration) (time.Duration, bool) {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = defaultInitialBackoff
+	}
+	max := p.MaxBackoff
+	if max <= 0 {
+		max = defaultMaxBackoff
+	}
+
+	if retryAfter > 0 {
+		return retryAfter, retryAfter <= max
+	}
+
+	delay := initial
+	for i := 1; i < attempt && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay, true
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// rewindBody prepares req to be sent again. It reports false if the body
+// cannot be replayed.
+func rewindBody(req *http.Request) bool {
+	if req.Body == nil || req.Body == http.NoBody {
+		return true
+	}
+	if req.GetBody == nil {
+		return false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	req.Body = body
+	return true
+}
+
+// drainBody discards the rest of resp's body so the connection can be reused.
+func drainBody(resp *http.Response) {
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
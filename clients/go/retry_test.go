@@ -0,0 +1,171 @@
+package objectstorage
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryOnServerError(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"key":"test-key","size":13}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+	obj, err := client.PutObject("test-bucket", "test-key", []byte("Hello, World!"), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "test-key", obj.Key)
+	assert.Equal(t, []string{"Hello, World!", "Hello, World!", "Hello, World!"}, bodies)
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("upstream unavailable"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRetry(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+	err := client.DeleteObject("test-bucket", "test-key")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "upstream unavailable")
+	assert.Equal(t, 2, hits)
+}
+
+func TestRetryNotOnClientError(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+	_, err := client.GetObject("test-bucket", "test-key")
+	require.Error(t, err)
+	assert.Equal(t, 1, hits)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	p := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for attempt, want := range map[int]time.Duration{
+		1:  100 * time.Millisecond,
+		2:  200 * time.Millisecond,
+		3:  400 * time.Millisecond,
+		10: time.Second,
+	} {
+		delay, ok := p.backoff(attempt, 0)
+		assert.True(t, ok)
+		assert.Equal(t, want, delay)
+	}
+
+	delay, ok := p.backoff(1, 300*time.Millisecond)
+	assert.True(t, ok)
+	assert.Equal(t, 300*time.Millisecond, delay)
+
+	_, ok = p.backoff(1, time.Minute)
+	assert.False(t, ok)
+}
+
+func TestRetryRateLimited(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if hits == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+	require.NoError(t, client.DeleteObject("test-bucket", "test-key"))
+	assert.Equal(t, 2, hits)
+}
+
+func TestRateLimitedRetryAfterExceedsBudget(t *testing.T) {
+	var hits int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("slow down"))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRetry(RetryPolicy{MaxAttempts: 3, MaxBackoff: time.Second}))
+	err := client.DeleteObject("test-bucket", "test-key")
+	require.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, 1, hits)
+
+	var objErr *Error
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, 120*time.Second, objErr.RetryAfter)
+}
+
+func TestRetryConnectionRefusedFailsFast(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	var attempts int
+	client := NewClient(url,
+		WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}),
+		WithFaultInjector(func(op string, attempt int) error {
+			attempts = attempt
+			return nil
+		}),
+	)
+	err := client.Ping()
+	require.Error(t, err)
+	assert.True(t, IsConnectionError(err))
+	assert.Equal(t, 1, attempts)
+
+	client = NewClient(url,
+		WithRetry(RetryPolicy{
+			MaxAttempts:      3,
+			InitialBackoff:   time.Millisecond,
+			ShouldRetryError: func(error) bool { return true },
+		}),
+		WithFaultInjector(func(op string, attempt int) error {
+			attempts = attempt
+			return nil
+		}),
+	)
+	require.Error(t, client.Ping())
+	assert.Equal(t, 3, attempts)
+}
+
+func TestDefaultShouldRetryError(t *testing.T) {
+	assert.True(t, DefaultShouldRetryError(&net.OpError{Op: "read", Err: &timeoutError{}}))
+	assert.True(t, DefaultShouldRetryError(io.ErrUnexpectedEOF))
+	assert.False(t, DefaultShouldRetryError(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}))
+	assert.False(t, DefaultShouldRetryError(&net.DNSError{Err: "no such host", Name: "storage.invalid"}))
+	assert.True(t, DefaultShouldRetryError(&net.DNSError{Err: "i/o timeout", Name: "storage.invalid", IsTimeout: true}))
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
@@ -0,0 +1,137 @@
+package objectstorage
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Servers differ in how they spell response fields: besides the snake_case
+// names used by this client, S3-compatible backends commonly send camelCase
+// ("lastModified") or PascalCase ("LastModified"). Responses are decoded
+// leniently by mapping every field onto its canonical name, ignoring case
+// and underscores.
+
+var (
+	bucketFields = schemaFields("id", "name", "created_at", "region")
+
+	objectMetadataFields = schemaFields("key", "size", "content_type",
+		"content_language", "etag", "last_modified", "storage_class",
+		"checksum_crc32c", "acl",
+		"content_encoding", "content_disposition", "cache_control",
+		"legal_hold", "website_redirect_location", "metadata",
+		"system_metadata")
+
+	listObjectsFields = schemaFields("objects", "common_prefixes",
+		"is_truncated", "next_continuation_token")
+
+	multipartUploadFields = schemaFields("key", "upload_id", "initiated",
+		"parts_count")
+
+	serverCapabilitiesFields = schemaFields("versioning", "multipart",
+		"tagging", "ranges", "copy", "append", "atomic_move", "object_lock",
+		"change_feed", "acl", "encryption")
+
+	completedPartFields = schemaFields("part_number", "etag", "content_md5",
+		"checksum_crc32c")
+
+	objectVersionFields = schemaFields("key", "version_id", "is_latest",
+		"is_delete_marker", "size", "etag", "last_modified")
+
+	listObjectVersionsFields = schemaFields("versions", "delete_markers",
+		"next_continuation_token")
+)
+
+// schemaFields maps the normalized form of each canonical field name to the
+// name itself.
+func schemaFields(names ...string) map[string]string {
+	fields := make(map[string]string, len(names))
+	for _, name := range names {
+		fields[normalizeFieldName(name)] = name
+	}
+	return fields
+}
+
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// canonicalField returns the canonical spelling of name, or name itself if
+// it is not a known field.
+func canonicalField(fields map[string]string, name string) string {
+	if canonical, ok := fields[normalizeFieldName(name)]; ok {
+		return canonical
+	}
+	return name
+}
+
+// unmarshalSchema decodes the JSON object in data into v after renaming its
+// fields to their canonical spelling. v must not implement json.Unmarshaler
+// itself.
+func unmarshalSchema(data []byte, fields map[string]string, v interface{}) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil || raw == nil {
+		return json.Unmarshal(data, v)
+	}
+
+	canonical := make(map[string]json.RawMessage, len(raw))
+	for name, value := range raw {
+		canonical[canonicalField(fields, name)] = value
+	}
+	data, err := json.Marshal(canonical)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func (b *Bucket) UnmarshalJSON(data []byte) error {
+	type plain Bucket
+	return unmarshalSchema(data, bucketFields, (*plain)(b))
+}
+
+func (m *ObjectMetadata) UnmarshalJSON(data []byte) error {
+	type plain ObjectMetadata
+	if err := unmarshalSchema(data, objectMetadataFields, (*plain)(m)); err != nil {
+		return err
+	}
+	m.ETag = opaqueETag(m.ETag)
+	return nil
+}
+
+func (r *listObjectsResponse) UnmarshalJSON(data []byte) error {
+	type plain listObjectsResponse
+	return unmarshalSchema(data, listObjectsFields, (*plain)(r))
+}
+
+func (u *MultipartUpload) UnmarshalJSON(data []byte) error {
+	type plain MultipartUpload
+	return unmarshalSchema(data, multipartUploadFields, (*plain)(u))
+}
+
+func (v *ObjectVersion) UnmarshalJSON(data []byte) error {
+	type plain ObjectVersion
+	if err := unmarshalSchema(data, objectVersionFields, (*plain)(v)); err != nil {
+		return err
+	}
+	v.ETag = opaqueETag(v.ETag)
+	return nil
+}
+
+func (r *listObjectVersionsResponse) UnmarshalJSON(data []byte) error {
+	type plain listObjectVersionsResponse
+	return unmarshalSchema(data, listObjectVersionsFields, (*plain)(r))
+}
+
+func (p *CompletedPart) UnmarshalJSON(data []byte) error {
+	type plain CompletedPart
+	if err := unmarshalSchema(data, completedPartFields, (*plain)(p)); err != nil {
+		return err
+	}
+	p.ETag = opaqueETag(p.ETag)
+	return nil
+}
+
+func (c *ServerCapabilities) UnmarshalJSON(data []byte) error {
+	type plain ServerCapabilities
+	return unmarshalSchema(data, serverCapabilitiesFields, (*plain)(c))
+}
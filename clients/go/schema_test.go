@@ -0,0 +1,58 @@
+package objectstorage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectMetadataAlternativeFieldNames(t *testing.T) {
+	for _, body := range []string{
+		`{"key":"a","size":1,"last_modified":"2024-01-01T00:00:00Z","content_type":"text/plain"}`,
+		`{"key":"a","size":1,"lastModified":"2024-01-01T00:00:00Z","contentType":"text/plain"}`,
+		`{"Key":"a","Size":1,"LastModified":"2024-01-01T00:00:00Z","ContentType":"text/plain"}`,
+	} {
+		var obj ObjectMetadata
+		require.NoError(t, json.Unmarshal([]byte(body), &obj), body)
+		assert.Equal(t, "a", obj.Key, body)
+		assert.Equal(t, uint64(1), obj.Size, body)
+		assert.Equal(t, "2024-01-01T00:00:00Z", obj.LastModified, body)
+		require.NotNil(t, obj.ContentType, body)
+		assert.Equal(t, "text/plain", *obj.ContentType, body)
+	}
+}
+
+func TestListObjectsCamelCase(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("continuation_token") == "" {
+			fmt.Fprint(w, `{"Objects":[{"Key":"a","Size":1,"ETag":"e1","LastModified":"t1"}],"commonPrefixes":["dir/"],"isTruncated":true,"nextContinuationToken":"page-2"}`)
+			return
+		}
+		fmt.Fprint(w, `{"objects":[{"key":"b","size":2,"lastModified":"t2"}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	page, err := client.List("test-bucket", ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, page.Objects, 1)
+	assert.Equal(t, "e1", page.Objects[0].ETag)
+	assert.Equal(t, "t1", page.Objects[0].LastModified)
+	assert.Equal(t, []string{"dir/"}, page.CommonPrefixes)
+	assert.True(t, page.IsTruncated)
+
+	var seen []ObjectMetadata
+	err = client.ForEachObject(context.Background(), "test-bucket", nil, func(obj ObjectMetadata) error {
+		seen = append(seen, obj)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, seen, 2)
+	assert.Equal(t, "t2", seen[1].LastModified)
+}
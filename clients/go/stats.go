@@ -0,0 +1,112 @@
+package objectstorage
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// statsWindow is the number of recent latency samples kept per operation for
+// computing percentiles.
+const statsWindow = 1024
+
+// OperationStats summarizes the requests made by one client method.
+type OperationStats struct {
+	// Count is the number of calls, including failed ones. Errors counts
+	// the calls that failed in transport or got a 4xx or 5xx response.
+	Count  int64
+	Errors int64
+	// P50, P95 and P99 are latency percentiles over the most recent calls,
+	// measured until the response headers arrived, including retries.
+	P50 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+}
+
+type statsCollector struct {
+	mu  sync.Mutex
+	ops map[string]*opStats
+}
+
+type opStats struct {
+	count     int64
+	errors    int64
+	latencies [statsWindow]time.Duration
+	next      int
+}
+
+// WithStats enables collection of per-operation call counts, error counts
+// and latencies. Read them with Stats.
+func WithStats() Option {
+	return func(c *Client) {
+		c.stats = &statsCollector{ops: make(map[string]*opStats)}
+	}
+}
+
+// Stats returns a snapshot of the statistics gathered so far, keyed by
+// operation name such as "PutObject". It returns nil unless the client was
+// created with WithStats.
+func (c *Client) Stats() map[string]OperationStats {
+	if c.stats == nil {
+		return nil
+	}
+
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	snapshot := make(map[string]OperationStats, len(c.stats.ops))
+	for op, s := range c.stats.ops {
+		n := int(s.count)
+		if n > statsWindow {
+			n = statsWindow
+		}
+		sorted := make([]time.Duration, n)
+		copy(sorted, s.latencies[:n])
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+		snapshot[op] = OperationStats{
+			Count:  s.count,
+			Errors: s.errors,
+			P50:    percentile(sorted, 50),
+			P95:    percentile(sorted, 95),
+			P99:    percentile(sorted, 99),
+		}
+	}
+	return snapshot
+}
+
+// record adds the outcome of one call to op.
+func (s *statsCollector) record(op string, latency time.Duration, resp *http.Response, err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o := s.ops[op]
+	if o == nil {
+		o = &opStats{}
+		s.ops[op] = o
+	}
+	o.count++
+	if err != nil || resp.StatusCode >= 400 {
+		o.errors++
+	}
+	o.latencies[o.next] = latency
+	o.next = (o.next + 1) % statsWindow
+}
+
+// percentile returns the p-th percentile of sorted using the nearest-rank
+// method, or zero if sorted is empty.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
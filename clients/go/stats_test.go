@@ -0,0 +1,54 @@
+package objectstorage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		time.Sleep(2 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	assert.Nil(t, NewClient(server.URL).Stats())
+
+	client := NewClient(server.URL, WithStats())
+	for i := 0; i < 10; i++ {
+		require.NoError(t, client.Ping())
+	}
+	assert.Error(t, client.DeleteObject("test-bucket", "test-key"))
+
+	stats := client.Stats()
+	ping := stats["Ping"]
+	assert.Equal(t, int64(10), ping.Count)
+	assert.Zero(t, ping.Errors)
+	assert.GreaterOrEqual(t, ping.P50, 2*time.Millisecond)
+	assert.LessOrEqual(t, ping.P50, ping.P95)
+	assert.LessOrEqual(t, ping.P95, ping.P99)
+
+	del := stats["DeleteObject"]
+	assert.Equal(t, int64(1), del.Count)
+	assert.Equal(t, int64(1), del.Errors)
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i))
+	}
+	assert.Equal(t, time.Duration(50), percentile(sorted, 50))
+	assert.Equal(t, time.Duration(95), percentile(sorted, 95))
+	assert.Equal(t, time.Duration(99), percentile(sorted, 99))
+	assert.Equal(t, time.Duration(1), percentile(sorted[:1], 99))
+	assert.Zero(t, percentile(nil, 50))
+}
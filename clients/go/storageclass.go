@@ -0,0 +1,160 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type restoreObjectRequest struct {
+	Days int `json:"days"`
+}
+
+// RestoreObject requests a temporary copy of an archived object that stays
+// readable for the given number of days. Restoration is asynchronous; the
+// server accepts the request before the copy is available.
+func (c *Client) RestoreObject(bucket, key string, days int) error {
+	reqBody := restoreObjectRequest{Days: days}
+	body, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "POST", "objects", bucket, key, "restore", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do("RestoreObject", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		return c.newError(resp)
+	}
+
+	return nil
+}
+
+// TransitionStorageClass moves an object to another storage class in place,
+// by copying it onto itself with the new class, which the server treats as a
+// transition. Content and metadata are kept.
+//
+// The self-copy must be made by the server: a copy through the client would
+// overwrite the object while reading it. TransitionStorageClass therefore
+// returns ErrNotSupported unless Capabilities has reported server-side copy
+// support. Like CopyObject, it fails with ErrPreconditionFailed if the object
+// is replaced concurrently and with an error wrapping ErrCopyMismatch if the
+// result does not have the object's size and ETag.
+func (c *Client) TransitionStorageClass(bucket, key, class string) error {
+	if err := c.requireConfirmedCapability(supportsCopy); err != nil {
+		return err
+	}
+	_, err := c.CopyObject(bucket, key, bucket, key, WithStorageClass(class))
+	return err
+}
+
+// RestoreStatus describes the restore state of an object, as reported by the
+// x-object-restore header.
+type RestoreStatus struct {
+	// Requested is true if a restore has been requested for the object.
+	Requested bool
+	// InProgress is true while the temporary copy is being prepared.
+	InProgress bool
+	// ExpiresAt is when the restored copy expires, once it is available.
+	ExpiresAt time.Time
+	// StorageClass is the object's storage class.
+	StorageClass string
+}
+
+// Retrievable reports whether the object's content can be read: it is not
+// archived, or a restored copy is available.
+func (s *RestoreStatus) Retrievable() bool {
+	if s.Requested {
+		return !s.InProgress
+	}
+	return !strings.EqualFold(s.StorageClass, "archive")
+}
+
+// GetRestoreStatus reports whether a restore of an archived object is in
+// progress or complete, and when the restored copy expires.
+func (c *Client) GetRestoreStatus(bucket, key string) (*RestoreStatus, error) {
+	return c.getRestoreStatus(context.Background(), bucket, key)
+}
+
+// WaitForRestore polls the restore status of an object every poll interval
+// until its content is retrievable or ctx is done. For an archived object,
+// request the restore with RestoreObject first; otherwise it only returns
+// when ctx is done.
+func (c *Client) WaitForRestore(ctx context.Context, bucket, key string, poll time.Duration) error {
+	for {
+		status, err := c.getRestoreStatus(ctx, bucket, key)
+		if err != nil {
+			return err
+		}
+		if status.Retrievable() {
+			return nil
+		}
+		if err := c.sleep(ctx, poll); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *Client) getRestoreStatus(ctx context.Context, bucket, key string) (*RestoreStatus, error) {
+	req, err := c.newObjectRequest(ctx, "HEAD", "objects", bucket, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do("GetRestoreStatus", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.errorFromResponse(resp, http.StatusText(resp.StatusCode))
+	}
+
+	status := parseRestoreHeader(resp.Header.Get("x-object-restore"))
+	status.StorageClass = resp.Header.Get("x-object-storage-class")
+	return status, nil
+}
+
+// parseRestoreHeader parses an x-object-restore value such as
+// `ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"`.
+func parseRestoreHeader(v string) *RestoreStatus {
+	status := &RestoreStatus{}
+	if v == "" {
+		return status
+	}
+	status.Requested = true
+
+	for v != "" {
+		var name, value string
+		name, v, _ = strings.Cut(v, "=")
+		name = strings.TrimSpace(strings.TrimLeft(name, ", "))
+		if strings.HasPrefix(v, `"`) {
+			value, v, _ = strings.Cut(v[1:], `"`)
+		} else {
+			value, v, _ = strings.Cut(v, ",")
+		}
+
+		switch strings.ToLower(name) {
+		case "ongoing-request":
+			status.InProgress = value == "true"
+		case "expiry-date":
+			if t, err := http.ParseTime(value); err == nil {
+				status.ExpiresAt = t
+			}
+		}
+	}
+	return status
+}
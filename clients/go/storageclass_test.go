@@ -0,0 +1,164 @@
+package objectstorage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPutObjectWithStorageClass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "archive", r.Header.Get("x-object-storage-class"))
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(ObjectMetadata{
+			Key:          "test-key",
+			StorageClass: "archive",
+		})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.PutObject("test-bucket", "test-key", []byte("data"), nil, nil, WithStorageClass("archive"))
+	require.NoError(t, err)
+	assert.Equal(t, "archive", obj.StorageClass)
+}
+
+func TestHeadObjectStorageClass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-object-storage-class", "cold")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.HeadObject("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, "cold", obj.StorageClass)
+}
+
+func TestTransitionStorageClass(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/buckets/test-bucket/objects/test-key", r.URL.Path)
+		switch r.Method {
+		case "HEAD":
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Length", "4")
+			w.Header().Set("X-Object-Storage-Class", "hot")
+		case "PUT":
+			assert.Equal(t, "test-bucket/test-key", r.Header.Get("x-object-copy-source"))
+			assert.Equal(t, `"v1"`, r.Header.Get("x-object-copy-source-if-match"))
+			assert.Equal(t, "cold", r.Header.Get("x-object-storage-class"))
+			json.NewEncoder(w).Encode(ObjectMetadata{Key: "test-key", Size: 4, ETag: "v1", StorageClass: "cold"})
+		default:
+			t.Errorf("unexpected %s", r.Method)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{Copy: true})
+	require.NoError(t, client.TransitionStorageClass("test-bucket", "test-key", "cold"))
+}
+
+func TestTransitionStorageClassNotSupported(t *testing.T) {
+	server := newMemObjectServer(t)
+	defer server.Close()
+	server.put("test-bucket/test-key", "data", nil)
+
+	client := NewClient(server.URL)
+	err := client.TransitionStorageClass("test-bucket", "test-key", "cold")
+	assert.ErrorIs(t, err, ErrNotSupported)
+	assert.Empty(t, server.requests)
+	assert.Equal(t, "data", string(server.get("test-bucket/test-key").data))
+}
+
+func TestTransitionStorageClassChanged(t *testing.T) {
+	// A server that claims copy support but stores the empty request body.
+	server := newMemObjectServer(t)
+	defer server.Close()
+	server.put("test-bucket/test-key", "data", nil)
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{Copy: true})
+	err := client.TransitionStorageClass("test-bucket", "test-key", "cold")
+	assert.ErrorIs(t, err, ErrCopyMismatch)
+}
+
+func TestRestoreObject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/buckets/test-bucket/objects/test-key", r.URL.Path)
+		assert.Equal(t, "restore", r.URL.RawQuery)
+
+		var req restoreObjectRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, 7, req.Days)
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	require.NoError(t, client.RestoreObject("test-bucket", "test-key", 7))
+}
+
+func TestParseRestoreHeader(t *testing.T) {
+	status := parseRestoreHeader(`ongoing-request="true"`)
+	assert.True(t, status.Requested)
+	assert.True(t, status.InProgress)
+
+	status = parseRestoreHeader(`ongoing-request="false", expiry-date="Fri, 21 Dec 2012 00:00:00 GMT"`)
+	assert.False(t, status.InProgress)
+	assert.Equal(t, time.Date(2012, 12, 21, 0, 0, 0, 0, time.UTC), status.ExpiresAt)
+
+	assert.False(t, parseRestoreHeader("").Requested)
+}
+
+func TestWaitForRestore(t *testing.T) {
+	var polls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "HEAD", r.Method)
+		polls++
+		w.Header().Set("x-object-storage-class", "archive")
+		if polls < 3 {
+			w.Header().Set("x-object-restore", `ongoing-request="true"`)
+			return
+		}
+		w.Header().Set("x-object-restore", `ongoing-request="false", expiry-date="Fri, 21 Dec 2029 00:00:00 GMT"`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	status, err := client.GetRestoreStatus("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.True(t, status.InProgress)
+	assert.False(t, status.Retrievable())
+
+	require.NoError(t, client.WaitForRestore(context.Background(), "test-bucket", "test-key", time.Millisecond))
+	assert.Equal(t, 3, polls)
+
+	status, err = client.GetRestoreStatus("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.True(t, status.Retrievable())
+	assert.Equal(t, 2029, status.ExpiresAt.Year())
+}
+
+func TestWaitForRestoreArchivedWithoutRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-object-storage-class", "archive")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := client.WaitForRestore(ctx, "test-bucket", "test-key", time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
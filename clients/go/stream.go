@@ -0,0 +1,194 @@
+package objectstorage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrIncompleteUpload is returned when a streamed upload body yields a
+// different number of bytes than the size declared for it.
+var ErrIncompleteUpload = errors.New("objectstorage: upload body does not match declared size")
+
+// PutObjectStream uploads size bytes read from r. If size is negative the body
+// is sent with chunked encoding and its length is not checked.
+//
+// When r ends early, fails, or produces more than size bytes, the request is
+// aborted and an error wrapping ErrIncompleteUpload is returned, so a
+// truncated object is never reported as successfully stored.
+func (c *Client) PutObjectStream(bucket, key string, r io.Reader, size int64, contentType *string, metadata map[string]string) (*ObjectMetadata, error) {
+	newBody := func() *countingReader {
+		return &countingReader{r: r, size: size}
+	}
+	return c.putStream("PutObjectStream", bucket, key, size, contentType, metadata, newBody, false)
+}
+
+// PutObjectReaderAt uploads the first size bytes of r. Every attempt reads
+// from offset 0 through a fresh io.SectionReader, so unlike PutObjectStream
+// the upload can be retried under WithRetry without buffering the content.
+// Size mismatches are reported as for PutObjectStream.
+func (c *Client) PutObjectReaderAt(bucket, key string, r io.ReaderAt, size int64, contentType *string, metadata map[string]string) (*ObjectMetadata, error) {
+	newBody := func() *countingReader {
+		return &countingReader{r: io.NewSectionReader(r, 0, size), size: size}
+	}
+	return c.putStream("PutObjectReaderAt", bucket, key, size, contentType, metadata, newBody, true)
+}
+
+// putStream uploads the body produced by newBody. If replayable is set,
+// newBody is called again for every retry.
+func (c *Client) putStream(op, bucket, key string, size int64, contentType *string, metadata map[string]string, newBody func() *countingReader, replayable bool) (*ObjectMetadata, error) {
+	if err := c.validateMetadata(metadata); err != nil {
+		return nil, err
+	}
+
+	// The transport may call GetBody on its own goroutine.
+	var current atomic.Pointer[countingReader]
+	current.Store(newBody())
+
+	req, err := c.newObjectRequest(context.Background(), "PUT", "objects", bucket, key, "", current.Load())
+	if err != nil {
+		return nil, err
+	}
+	req.ContentLength = size
+	if replayable {
+		req.GetBody = func() (io.ReadCloser, error) {
+			body := newBody()
+			current.Store(body)
+			return io.NopCloser(body), nil
+		}
+	}
+	if c.expectContinueThreshold > 0 && size >= c.expectContinueThreshold {
+		req.Header.Set("Expect", "100-continue")
+	}
+
+	if contentType != nil {
+		req.Header.Set("Content-Type", *contentType)
+	}
+
+	setMetadataHeaders(req.Header, metadata)
+
+	resp, err := c.do(op, req)
+	body := current.Load()
+	if err != nil {
+		if bodyErr := body.failed(); bodyErr != nil {
+			return nil, bodyErr
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if bodyErr := body.failed(); bodyErr != nil {
+		return nil, bodyErr
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.newError(resp)
+	}
+
+	if bodyErr := body.check(); bodyErr != nil {
+		return nil, bodyErr
+	}
+
+	var objMetadata ObjectMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&objMetadata); err != nil {
+		return nil, err
+	}
+	objMetadata.RequestID = resp.Header.Get("X-Request-Id")
+
+	return &objMetadata, nil
+}
+
+// PutObjectStreamCT is PutObjectStream with a plain content type; an empty
+// contentType leaves it unset.
+func (c *Client) PutObjectStreamCT(bucket, key string, r io.Reader, size int64, contentType string, metadata map[string]string) (*ObjectMetadata, error) {
+	return c.PutObjectStream(bucket, key, r, size, optionalString(contentType), metadata)
+}
+
+// countingReader tracks how many bytes have been read from r and turns a
+// premature EOF or an overrun of size into an error, which makes the HTTP
+// transport abort the request instead of completing it.
+//
+// Read runs on the transport's goroutine, which net/http does not guarantee
+// has stopped reading when Client.Do returns, so the other methods must be
+// used to inspect the outcome.
+type countingReader struct {
+	r    io.Reader
+	size int64
+	// hash, if set, receives every byte read.
+	hash hash.Hash
+
+	mu  sync.Mutex
+	n   int64
+	err error
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	if err := cr.failed(); err != nil {
+		return 0, err
+	}
+
+	n, err := cr.r.Read(p)
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.n += int64(n)
+	if cr.hash != nil {
+		cr.hash.Write(p[:n])
+	}
+
+	if cr.size >= 0 && cr.n > cr.size {
+		cr.err = fmt.Errorf("%w: read more than %d bytes", ErrIncompleteUpload, cr.size)
+		return n, cr.err
+	}
+
+	switch {
+	case err == io.EOF:
+		if cr.size >= 0 && cr.n < cr.size {
+			cr.err = fmt.Errorf("%w: read %d of %d bytes: %w", ErrIncompleteUpload, cr.n, cr.size, io.ErrUnexpectedEOF)
+			return n, cr.err
+		}
+	case err != nil:
+		cr.err = fmt.Errorf("%w: read %d of %d bytes: %w", ErrIncompleteUpload, cr.n, cr.size, err)
+		return n, cr.err
+	}
+
+	return n, err
+}
+
+// failed returns the error recorded by Read, if any.
+func (cr *countingReader) failed() error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	return cr.err
+}
+
+// check reports any body error recorded during the request, including a body
+// that was not fully consumed by the transport.
+func (cr *countingReader) check() error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	if cr.err != nil {
+		return cr.err
+	}
+	if cr.size >= 0 && cr.n != cr.size {
+		return fmt.Errorf("%w: sent %d of %d bytes", ErrIncompleteUpload, cr.n, cr.size)
+	}
+	return nil
+}
+
+// sum returns the number of bytes read so far and, if hash is set, their
+// digest.
+func (cr *countingReader) sum() (int64, []byte) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	if cr.hash == nil {
+		return cr.n, nil
+	}
+	return cr.n, cr.hash.Sum(nil)
+}
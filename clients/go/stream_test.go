@@ -0,0 +1,154 @@
+package objectstorage
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPutObjectStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "PUT", r.Method)
+		assert.Equal(t, "/buckets/test-bucket/objects/test-key", r.URL.Path)
+		assert.Equal(t, int64(13), r.ContentLength)
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "Hello, World!", string(body))
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"key":"test-key","size":13,"etag":"abc123","last_modified":"2024-01-01T00:00:00Z","metadata":{}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	obj, err := client.PutObjectStream("test-bucket", "test-key", strings.NewReader("Hello, World!"), 13, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(13), obj.Size)
+}
+
+func TestPutObjectStreamShortBody(t *testing.T) {
+	var stored bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		stored = true
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"key":"test-key","size":5}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.PutObjectStream("test-bucket", "test-key", strings.NewReader("Hello"), 13, nil, nil)
+	require.ErrorIs(t, err, ErrIncompleteUpload)
+	assert.False(t, stored)
+}
+
+func TestPutObjectStreamReaderError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"key":"test-key"}`))
+	}))
+	defer server.Close()
+
+	readErr := errors.New("disk on fire")
+	r := io.MultiReader(strings.NewReader("Hello"), &failingReader{err: readErr})
+
+	client := NewClient(server.URL)
+	_, err := client.PutObjectStream("test-bucket", "test-key", r, 13, nil, nil)
+	require.ErrorIs(t, err, ErrIncompleteUpload)
+	assert.ErrorIs(t, err, readErr)
+}
+
+type failingReader struct {
+	err error
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	return 0, f.err
+}
+
+func TestPutObjectStreamExpectContinueRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "100-continue", r.Header.Get("Expect"))
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("quota exceeded"))
+	}))
+	defer server.Close()
+
+	body := &recordingReader{r: strings.NewReader(strings.Repeat("x", 1<<20))}
+	client := NewClient(server.URL, WithExpectContinue(1024))
+	_, err := client.PutObjectStream("test-bucket", "test-key", body, 1<<20, nil, nil)
+
+	var objErr *Error
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, http.StatusForbidden, objErr.StatusCode)
+	assert.Equal(t, int64(0), body.n)
+}
+
+func TestPutObjectStreamExpectContinueBelowThreshold(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Expect"))
+		io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"key":"test-key"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithExpectContinue(1024))
+	_, err := client.PutObjectStream("test-bucket", "test-key", strings.NewReader("small"), 5, nil, nil)
+	require.NoError(t, err)
+}
+
+type recordingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *recordingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func TestPutObjectReaderAtRetries(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"key":"test-key","size":13}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithRetry(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+	obj, err := client.PutObjectReaderAt("test-bucket", "test-key", strings.NewReader("Hello, World!"), 13, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(13), obj.Size)
+	assert.Equal(t, []string{"Hello, World!", "Hello, World!"}, bodies)
+}
+
+func TestPutObjectReaderAtShortSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.ReadAll(r.Body)
+		w.Write([]byte(`{"key":"test-key"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.PutObjectReaderAt("test-bucket", "test-key", strings.NewReader("short"), 13, nil, nil)
+	assert.ErrorIs(t, err, ErrIncompleteUpload)
+}
@@ -0,0 +1,218 @@
+package objectstorage
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SyncOptions selects which actions SyncPrefix may take.
+type SyncOptions struct {
+	// Upload sends local files that are missing or different remotely.
+	Upload bool
+	// Download fetches remote objects that are missing or different locally.
+	Download bool
+	// DeleteLocal removes local files that have no remote counterpart instead
+	// of uploading them.
+	DeleteLocal bool
+	// DeleteRemote removes remote objects that have no local counterpart
+	// instead of downloading them.
+	DeleteRemote bool
+	// Progress, if set, receives the sizes of files to upload and objects to
+	// download, and the bytes moved as transfers proceed.
+	Progress *ProgressAggregator
+}
+
+// SyncReport lists the keys affected by SyncPrefix, relative to the prefix.
+type SyncReport struct {
+	Uploaded      []string
+	Downloaded    []string
+	DeletedLocal  []string
+	DeletedRemote []string
+	Unchanged     []string
+	// Errors holds per-key failures. A failure for one key does not stop the
+	// sync of the others.
+	Errors map[string]error
+}
+
+type localFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// SyncPrefix reconciles the objects under prefix with the files under
+// localDir. Objects map to files by stripping prefix from the key; keys that
+// would escape localDir are skipped.
+//
+// Objects and files are considered equal when their size and ETag (the hex
+// SHA-256 of the content) match. When both Upload and Download are enabled
+// and a key differs, the side with the newer modification time wins.
+func (c *Client) SyncPrefix(bucket, prefix, localDir string, opts SyncOptions) (*SyncReport, error) {
+	remote := make(map[string]ObjectMetadata)
+	err := c.ForEachObject(context.Background(), bucket, &prefix, func(obj ObjectMetadata) error {
+		rel := strings.TrimPrefix(obj.Key, prefix)
+		if rel == "" || strings.HasSuffix(rel, "/") || !filepath.IsLocal(filepath.FromSlash(rel)) {
+			return nil
+		}
+		remote[rel] = obj
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	local := make(map[string]localFile)
+	err = filepath.WalkDir(localDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == localDir && os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(localDir, p)
+		if err != nil {
+			return err
+		}
+		local[filepath.ToSlash(rel)] = localFile{path: p, size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SyncReport{Errors: make(map[string]error)}
+	upload := func(rel string, f localFile) {
+		if err := c.uploadFile(bucket, prefix+rel, f, opts.Progress); err != nil {
+			report.Errors[rel] = err
+			return
+		}
+		report.Uploaded = append(report.Uploaded, rel)
+	}
+	download := func(rel string) {
+		if err := c.downloadFile(bucket, prefix+rel, filepath.Join(localDir, filepath.FromSlash(rel)), opts.Progress); err != nil {
+			report.Errors[rel] = err
+			return
+		}
+		report.Downloaded = append(report.Downloaded, rel)
+	}
+
+	for rel, f := range local {
+		obj, ok := remote[rel]
+		switch {
+		case !ok && opts.DeleteLocal:
+			if err := os.Remove(f.path); err != nil {
+				report.Errors[rel] = err
+				continue
+			}
+			report.DeletedLocal = append(report.DeletedLocal, rel)
+		case !ok && opts.Upload:
+			upload(rel, f)
+		case !ok:
+			// Local-only file, but neither uploads nor deletes are enabled.
+		default:
+			same, err := sameContent(f, obj)
+			if err != nil {
+				report.Errors[rel] = err
+				continue
+			}
+			if same {
+				report.Unchanged = append(report.Unchanged, rel)
+				continue
+			}
+
+			remoteTime, _ := parseLastModified(obj.LastModified)
+			switch {
+			case opts.Upload && opts.Download:
+				if f.modTime.After(remoteTime) {
+					upload(rel, f)
+				} else {
+					download(rel)
+				}
+			case opts.Upload:
+				upload(rel, f)
+			case opts.Download:
+				download(rel)
+			}
+		}
+	}
+
+	for rel := range remote {
+		if _, ok := local[rel]; ok {
+			continue
+		}
+		switch {
+		case opts.DeleteRemote:
+			if err := c.DeleteObject(bucket, prefix+rel); err != nil {
+				report.Errors[rel] = err
+				continue
+			}
+			report.DeletedRemote = append(report.DeletedRemote, rel)
+		case opts.Download:
+			download(rel)
+		}
+	}
+
+	for _, keys := range [][]string{report.Uploaded, report.Downloaded, report.DeletedLocal, report.DeletedRemote, report.Unchanged} {
+		sort.Strings(keys)
+	}
+
+	return report, nil
+}
+
+func (c *Client) uploadFile(bucket, key string, f localFile, progress *ProgressAggregator) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	progress.Expect(f.size)
+	_, err = c.PutObjectStream(bucket, key, progress.reader(file, c.now), f.size, nil, nil)
+	return err
+}
+
+func (c *Client) downloadFile(bucket, key, dest string, progress *ProgressAggregator) error {
+	obj, err := c.GetObject(bucket, key)
+	if err != nil {
+		return err
+	}
+	progress.Expect(int64(len(obj.Data)))
+	progress.addAt(int64(len(obj.Data)), c.now())
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, obj.Data, 0o644)
+}
+
+func sameContent(f localFile, obj ObjectMetadata) (bool, error) {
+	if uint64(f.size) != obj.Size {
+		return false, nil
+	}
+	if obj.ETag == "" {
+		return false, nil
+	}
+
+	file, err := os.Open(f.path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	etag, err := ComputeETagReader(file)
+	if err != nil {
+		return false, err
+	}
+	return ETagEqual(etag, obj.ETag), nil
+}
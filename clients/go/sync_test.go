@@ -0,0 +1,145 @@
+package objectstorage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is a minimal in-memory object server for exercising helpers
+// that issue several requests.
+type memoryStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemoryStore(objects map[string]string) *memoryStore {
+	s := &memoryStore{objects: make(map[string][]byte)}
+	for k, v := range objects {
+		s.objects[k] = []byte(v)
+	}
+	return s
+}
+
+func (s *memoryStore) meta(key string) ObjectMetadata {
+	sum := sha256.Sum256(s.objects[key])
+	return ObjectMetadata{
+		Key:          key,
+		Size:         uint64(len(s.objects[key])),
+		ETag:         hex.EncodeToString(sum[:]),
+		LastModified: "2024-01-01T00:00:00Z",
+		Metadata:     map[string]string{},
+	}
+}
+
+func (s *memoryStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	const objectsPrefix = "/buckets/test-bucket/objects"
+	if r.URL.Path == objectsPrefix && r.Method == "GET" {
+		prefix := r.URL.Query().Get("prefix")
+		var keys []string
+		for k := range s.objects {
+			if strings.HasPrefix(k, prefix) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		result := listObjectsResponse{Objects: []ObjectMetadata{}}
+		for _, k := range keys {
+			result.Objects = append(result.Objects, s.meta(k))
+		}
+		json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, objectsPrefix+"/")
+	switch r.Method {
+	case "PUT":
+		data, _ := io.ReadAll(r.Body)
+		s.objects[key] = data
+		json.NewEncoder(w).Encode(s.meta(key))
+	case "GET", "HEAD":
+		data, ok := s.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		meta := s.meta(key)
+		w.Header().Set("ETag", meta.ETag)
+		w.Header().Set("Last-Modified", meta.LastModified)
+		w.Write(data)
+	case "DELETE":
+		if _, ok := s.objects[key]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(s.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestSyncPrefix(t *testing.T) {
+	store := newMemoryStore(map[string]string{
+		"site/index.html":    "same",
+		"site/css/app.css":   "remote only",
+		"site/about.html":    "old remote",
+		"other/ignored.html": "outside prefix",
+	})
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("same"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "about.html"), []byte("new local content"), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "img"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "img", "logo.png"), []byte("png"), 0o644))
+
+	client := NewClient(server.URL)
+	report, err := client.SyncPrefix("test-bucket", "site/", dir, SyncOptions{Upload: true, Download: true})
+	require.NoError(t, err)
+	assert.Empty(t, report.Errors)
+	assert.Equal(t, []string{"about.html", "img/logo.png"}, report.Uploaded)
+	assert.Equal(t, []string{"css/app.css"}, report.Downloaded)
+	assert.Equal(t, []string{"index.html"}, report.Unchanged)
+
+	assert.Equal(t, "new local content", string(store.objects["site/about.html"]))
+	assert.Equal(t, "png", string(store.objects["site/img/logo.png"]))
+	data, err := os.ReadFile(filepath.Join(dir, "css", "app.css"))
+	require.NoError(t, err)
+	assert.Equal(t, "remote only", string(data))
+}
+
+func TestSyncPrefixDeleteExtraneous(t *testing.T) {
+	store := newMemoryStore(map[string]string{
+		"site/keep.txt":   "keep",
+		"site/remote.txt": "remote",
+	})
+	server := httptest.NewServer(store)
+	defer server.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "keep.txt"), []byte("keep"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "local.txt"), []byte("local"), 0o644))
+
+	client := NewClient(server.URL)
+	report, err := client.SyncPrefix("test-bucket", "site/", dir, SyncOptions{DeleteLocal: true, DeleteRemote: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"local.txt"}, report.DeletedLocal)
+	assert.Equal(t, []string{"remote.txt"}, report.DeletedRemote)
+	assert.NotContains(t, store.objects, "site/remote.txt")
+	assert.NoFileExists(t, filepath.Join(dir, "local.txt"))
+}
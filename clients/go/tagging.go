@@ -0,0 +1,119 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+type objectTagging struct {
+	Tags map[string]string `json:"tags"`
+}
+
+// PutObjectTags replaces the tag set of an object. Tags are stored
+// separately from user metadata and can be changed without rewriting the
+// object. It returns ErrNotSupported unless Capabilities has reported
+// tagging support, since a server without it would store the tags as the
+// object's content.
+func (c *Client) PutObjectTags(bucket, key string, tags map[string]string) error {
+	if err := c.requireConfirmedCapability(supportsTagging); err != nil {
+		return err
+	}
+	if tags == nil {
+		tags = map[string]string{}
+	}
+	body, err := json.Marshal(objectTagging{Tags: tags})
+	if err != nil {
+		return err
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "PUT", "objects", bucket, key, "tagging", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do("PutObjectTags", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return c.newError(resp)
+	}
+
+	return nil
+}
+
+// GetObjectTags returns the tag set of an object. It returns
+// ErrNotSupported unless Capabilities has reported tagging support.
+func (c *Client) GetObjectTags(bucket, key string) (map[string]string, error) {
+	if err := c.requireConfirmedCapability(supportsTagging); err != nil {
+		return nil, err
+	}
+
+	req, err := c.newObjectRequest(context.Background(), "GET", "objects", bucket, key, "tagging", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do("GetObjectTags", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.newError(resp)
+	}
+
+	var tagging objectTagging
+	if err := json.NewDecoder(resp.Body).Decode(&tagging); err != nil {
+		return nil, err
+	}
+	if tagging.Tags == nil {
+		tagging.Tags = map[string]string{}
+	}
+
+	return tagging.Tags, nil
+}
+
+// TagPrefix applies tags with PutObjectTags to every object under prefix,
+// with at most concurrency requests in flight. The returned error is set if
+// the prefix could not be listed, in which case no object was tagged, and is
+// ErrNotSupported unless Capabilities has reported tagging support.
+// Otherwise the map holds the keys whose tagging failed.
+func (c *Client) TagPrefix(bucket, prefix string, tags map[string]string, concurrency int) (map[string]error, error) {
+	if err := c.requireConfirmedCapability(supportsTagging); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	err := c.ForEachObject(context.Background(), bucket, &prefix, func(obj ObjectMetadata) error {
+		keys = append(keys, obj.Key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make(map[string]error)
+	var mu sync.Mutex
+
+	runConcurrent(keys, concurrency, func(key string) {
+		if err := c.PutObjectTags(bucket, key, tags); err != nil {
+			mu.Lock()
+			defer mu.Unlock()
+			errs[key] = err
+		}
+	})
+
+	return errs, nil
+}
+
+func supportsTagging(s *ServerCapabilities) bool {
+	return s.Tagging
+}
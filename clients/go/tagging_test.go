@@ -0,0 +1,94 @@
+package objectstorage
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectTags(t *testing.T) {
+	var stored objectTagging
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/buckets/test-bucket/objects/test-key", r.URL.Path)
+		_, ok := r.URL.Query()["tagging"]
+		assert.True(t, ok)
+
+		switch r.Method {
+		case "PUT":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&stored))
+			w.WriteHeader(http.StatusNoContent)
+		case "GET":
+			json.NewEncoder(w).Encode(stored)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{Tagging: true})
+	require.NoError(t, client.PutObjectTags("test-bucket", "test-key", map[string]string{"cost-center": "42"}))
+
+	tags, err := client.GetObjectTags("test-bucket", "test-key")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"cost-center": "42"}, tags)
+}
+
+func TestTagPrefix(t *testing.T) {
+	var mu sync.Mutex
+	tagged := map[string]map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/buckets/test-bucket/objects" {
+			assert.Equal(t, "project/", r.URL.Query().Get("prefix"))
+			fmt.Fprint(w, `{"objects":[{"key":"project/a"},{"key":"project/b"},{"key":"project/locked"}]}`)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/buckets/test-bucket/objects/")
+		if key == "project/locked" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		var body objectTagging
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		mu.Lock()
+		tagged[key] = body.Tags
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.capabilities.Store(&ServerCapabilities{Tagging: true})
+	errs, err := client.TagPrefix("test-bucket", "project/", map[string]string{"cost-center": "42"}, 2)
+	require.NoError(t, err)
+	assert.Len(t, errs, 1)
+	assert.Error(t, errs["project/locked"])
+	assert.Equal(t, map[string]map[string]string{
+		"project/a": {"cost-center": "42"},
+		"project/b": {"cost-center": "42"},
+	}, tagged)
+}
+
+func TestObjectTagsRequireCapability(t *testing.T) {
+	// memObjectServer ignores ?tagging like the real server: a PUT would
+	// replace the object with the tag set.
+	server := newMemObjectServer(t)
+	defer server.Close()
+	server.put("test-bucket/project/a", "hello", nil)
+
+	client := NewClient(server.URL)
+	tags := map[string]string{"cost-center": "42"}
+	assert.ErrorIs(t, client.PutObjectTags("test-bucket", "project/a", tags), ErrNotSupported)
+	_, err := client.GetObjectTags("test-bucket", "project/a")
+	assert.ErrorIs(t, err, ErrNotSupported)
+	_, err = client.TagPrefix("test-bucket", "project/", tags, 2)
+	assert.ErrorIs(t, err, ErrNotSupported)
+
+	assert.Empty(t, server.requests)
+	assert.Equal(t, "hello", string(server.get("test-bucket/project/a").data))
+}
@@ -0,0 +1,59 @@
+package objectstorage
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// WithClientCertificate authenticates the client to the server with the
+// PEM-encoded certificate and private key in certFile and keyFile, for
+// servers that require mutual TLS. The files are loaded when the client is
+// created; if that fails, every request fails with the loading error. It has
+// no effect if the HTTP client uses a custom RoundTripper.
+func WithClientCertificate(certFile, keyFile string) Option {
+	return func(c *Client) {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			c.setOptionError(fmt.Errorf("objectstorage: loading client certificate: %w", err))
+			return
+		}
+		if t := c.transport(); t != nil {
+			cfg := tlsConfig(t)
+			cfg.Certificates = append(cfg.Certificates, cert)
+		}
+	}
+}
+
+// WithRootCAs verifies the server's certificate against the PEM-encoded CA
+// certificates in caFile instead of the system roots. The file is loaded
+// when the client is created; if that fails, every request fails with the
+// loading error. It has no effect if the HTTP client uses a custom
+// RoundTripper.
+func WithRootCAs(caFile string) Option {
+	return func(c *Client) {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			c.setOptionError(fmt.Errorf("objectstorage: loading root CAs: %w", err))
+			return
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			c.setOptionError(fmt.Errorf("objectstorage: loading root CAs: no certificates found in %s", caFile))
+			return
+		}
+		if t := c.transport(); t != nil {
+			tlsConfig(t).RootCAs = pool
+		}
+	}
+}
+
+// tlsConfig returns t's TLS configuration, creating it if needed.
+func tlsConfig(t *http.Transport) *tls.Config {
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{}
+	}
+	return t.TLSClientConfig
+}
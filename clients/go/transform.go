@@ -0,0 +1,56 @@
+package objectstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// TransformObject downloads bucket/srcKey, passes its content through
+// transform and uploads the output as bucket/dstKey. Data is streamed through
+// an io.Pipe, so the object is never held in memory. The destination keeps
+// the source's content type and user metadata.
+//
+// If transform returns an error, the upload is aborted and the error is
+// returned; dstKey is left untouched. If the upload fails, writes to the
+// transform's io.Writer fail with io.ErrClosedPipe.
+func (c *Client) TransformObject(bucket, srcKey, dstKey string, transform func(io.Reader, io.Writer) error) error {
+	req, err := c.newObjectRequest(context.Background(), "GET", "objects", bucket, srcKey, "", nil)
+	if err != nil {
+		return err
+	}
+	acceptIdentity(req)
+
+	resp, err := c.do("TransformObject", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return c.newError(resp)
+	}
+	src := metadataFromHeaders(srcKey, resp.Header)
+
+	pr, pw := io.Pipe()
+	transformed := make(chan error, 1)
+	go func() {
+		err := transform(resp.Body, pw)
+		pw.CloseWithError(err)
+		transformed <- err
+	}()
+
+	newBody := func() *countingReader {
+		return &countingReader{r: pr, size: -1}
+	}
+	_, err = c.putStream("TransformObject", bucket, dstKey, -1, src.ContentType, src.Metadata, newBody, false)
+
+	// Unblock the transform if the upload stopped reading early.
+	pr.Close()
+	if terr := <-transformed; terr != nil && !errors.Is(terr, io.ErrClosedPipe) {
+		return fmt.Errorf("objectstorage: transforming %s: %w", srcKey, terr)
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+package objectstorage
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformObject(t *testing.T) {
+	var mu sync.Mutex
+	var uploaded []byte
+	var uploadHeader http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			assert.Equal(t, "/buckets/test-bucket/objects/src", r.URL.Path)
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("x-object-meta-owner", "alice")
+			w.Write([]byte("hello world"))
+		case "PUT":
+			assert.Equal(t, "/buckets/test-bucket/objects/dst", r.URL.Path)
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			mu.Lock()
+			uploaded, uploadHeader = body, r.Header
+			mu.Unlock()
+			w.Write([]byte(`{"key":"dst"}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	err := client.TransformObject("test-bucket", "src", "dst", func(r io.Reader, w io.Writer) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bytes.ToUpper(data))
+		return err
+	})
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "HELLO WORLD", string(uploaded))
+	assert.Equal(t, "text/plain", uploadHeader.Get("Content-Type"))
+	assert.Equal(t, "alice", uploadHeader.Get("x-object-meta-owner"))
+}
+
+func TestTransformObjectError(t *testing.T) {
+	var mu sync.Mutex
+	var stored bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write([]byte("hello world"))
+		case "PUT":
+			if _, err := io.ReadAll(r.Body); err != nil {
+				return
+			}
+			mu.Lock()
+			stored = true
+			mu.Unlock()
+			w.Write([]byte(`{"key":"dst"}`))
+		}
+	}))
+	defer server.Close()
+
+	boom := errors.New("boom")
+	client := NewClient(server.URL)
+	err := client.TransformObject("test-bucket", "src", "dst", func(r io.Reader, w io.Writer) error {
+		w.Write([]byte("partial"))
+		return boom
+	})
+	assert.ErrorIs(t, err, boom)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.False(t, stored)
+}
+
+func TestTransformObjectUploadRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "GET":
+			w.Write([]byte("hello world"))
+		case "PUT":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("quota exceeded"))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	err := client.TransformObject("test-bucket", "src", "dst", func(r io.Reader, w io.Writer) error {
+		_, err := io.Copy(w, r)
+		return err
+	})
+	var objErr *Error
+	require.True(t, errors.As(err, &objErr))
+	assert.Equal(t, http.StatusForbidden, objErr.StatusCode)
+}
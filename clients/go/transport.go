@@ -0,0 +1,91 @@
+package objectstorage
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// WithTimeout sets the overall timeout of each HTTP exchange. A zero d
+// disables it. NewClient defaults to 30 seconds.
+//
+// This maps to http.Client.Timeout, which bounds the whole exchange including
+// the time spent streaming the request and response bodies. It is therefore
+// the wrong knob for large uploads and downloads: use WithTimeout(0) together
+// with WithDialTimeout, WithResponseHeaderTimeout and per-call contexts
+// instead.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.ownHTTPClient().Timeout = d
+	}
+}
+
+// WithDialTimeout bounds how long establishing a TCP connection may take. It
+// has no effect if the HTTP client uses a custom RoundTripper.
+func WithDialTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		if t := c.transport(); t != nil {
+			t.DialContext = (&net.Dialer{Timeout: d, KeepAlive: 30 * time.Second}).DialContext
+			if c.dnsCache != nil {
+				t.DialContext = c.dnsCache.wrap(t.DialContext)
+			}
+		}
+	}
+}
+
+// WithResponseHeaderTimeout bounds how long to wait for the server's response
+// headers once the request, including its body, has been written. Unlike
+// WithTimeout it does not limit how long the body transfer takes. It has no
+// effect if the HTTP client uses a custom RoundTripper.
+func WithResponseHeaderTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		if t := c.transport(); t != nil {
+			t.ResponseHeaderTimeout = d
+		}
+	}
+}
+
+// WithDisableKeepAlives closes every connection after its request instead of
+// keeping it open for reuse. This suits short-lived processes such as CLI
+// tools, which would otherwise leave idle connections lingering at exit. It
+// has no effect if the HTTP client uses a custom RoundTripper.
+func WithDisableKeepAlives() Option {
+	return func(c *Client) {
+		if t := c.transport(); t != nil {
+			t.DisableKeepAlives = true
+		}
+	}
+}
+
+// ownHTTPClient returns an http.Client owned by c, copying the one passed to
+// NewClientWithHTTP on first use so options never modify the caller's client.
+func (c *Client) ownHTTPClient() *http.Client {
+	if !c.ownsHTTPClient {
+		hc := *c.httpClient
+		c.httpClient = &hc
+		c.ownsHTTPClient = true
+	}
+	return c.httpClient
+}
+
+// transport returns the client's own *http.Transport, cloning the configured
+// (or default) transport on first use. It returns nil if the HTTP client uses
+// a RoundTripper that is not an *http.Transport.
+func (c *Client) transport() *http.Transport {
+	hc := c.ownHTTPClient()
+	if c.ownsTransport {
+		return hc.Transport.(*http.Transport)
+	}
+
+	rt := hc.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return nil
+	}
+	hc.Transport = t.Clone()
+	c.ownsTransport = true
+	return hc.Transport.(*http.Transport)
+}
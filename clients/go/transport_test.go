@@ -0,0 +1,56 @@
+package objectstorage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithTimeoutZeroDisablesTimeout(t *testing.T) {
+	client := NewClient("http://example.test", WithTimeout(0))
+	assert.Zero(t, client.httpClient.Timeout)
+
+	client = NewClient("http://example.test")
+	assert.Equal(t, 30*time.Second, client.httpClient.Timeout)
+}
+
+func TestTransportOptionsDoNotModifyCallerClient(t *testing.T) {
+	hc := &http.Client{Timeout: time.Minute}
+	client := NewClientWithHTTP("http://example.test", hc, WithTimeout(0), WithResponseHeaderTimeout(time.Second))
+
+	assert.Equal(t, time.Minute, hc.Timeout)
+	assert.Nil(t, hc.Transport)
+	assert.Zero(t, client.httpClient.Timeout)
+	assert.Equal(t, time.Second, client.transport().ResponseHeaderTimeout)
+	assert.Zero(t, http.DefaultTransport.(*http.Transport).ResponseHeaderTimeout)
+}
+
+func TestWithResponseHeaderTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient(server.URL, WithTimeout(0), WithResponseHeaderTimeout(50*time.Millisecond))
+	err := client.Ping()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timeout awaiting response headers")
+}
+
+func TestWithDisableKeepAlives(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, r.Close)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, WithDisableKeepAlives(), WithConnTrace())
+	require.NoError(t, client.Ping())
+	require.NoError(t, client.Ping())
+	assert.Equal(t, int64(2), client.ConnStats().NewConns)
+}
@@ -0,0 +1,117 @@
+package objectstorage
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// ObjectVersion is one version of an object in a versioned bucket, either
+// stored content or a delete marker left by deleting the object.
+type ObjectVersion struct {
+	Key       string `json:"key"`
+	VersionID string `json:"version_id"`
+	// IsLatest reports whether this is the current version of the key.
+	IsLatest bool `json:"is_latest"`
+	// IsDeleteMarker reports that this version records a deletion and has
+	// no content; Size and ETag are then empty.
+	IsDeleteMarker bool   `json:"is_delete_marker"`
+	Size           uint64 `json:"size"`
+	ETag           string `json:"etag"`
+	LastModified   string `json:"last_modified"`
+}
+
+type listObjectVersionsResponse struct {
+	Versions []ObjectVersion `json:"versions"`
+	// DeleteMarkers is used by servers that report delete markers apart
+	// from versions, as S3 does.
+	DeleteMarkers         []ObjectVersion `json:"delete_markers"`
+	NextContinuationToken string          `json:"next_continuation_token,omitempty"`
+}
+
+// ListObjectVersions returns every version and delete marker of the objects
+// under prefix, paging through the whole listing. Delete markers have
+// IsDeleteMarker set whether the server reports them inline or in a
+// separate list.
+func (c *Client) ListObjectVersions(bucket string, prefix *string) ([]ObjectVersion, error) {
+	if err := c.requireCapability(supportsVersioning); err != nil {
+		return nil, err
+	}
+
+	params := url.Values{}
+	if prefix != nil {
+		params.Set("prefix", *prefix)
+	}
+	if c.keyEncoding == KeyEncodingURL {
+		params.Set("encoding-type", "url")
+	}
+
+	var versions []ObjectVersion
+	for {
+		page, err := c.listObjectVersions(bucket, params)
+		if err != nil {
+			return nil, err
+		}
+		for i := range page.DeleteMarkers {
+			page.DeleteMarkers[i].IsDeleteMarker = true
+		}
+		for _, v := range append(page.Versions, page.DeleteMarkers...) {
+			if v.Key, err = c.keyEncoding.decode(v.Key); err != nil {
+				return nil, err
+			}
+			versions = append(versions, v)
+		}
+		if page.NextContinuationToken == "" {
+			return versions, nil
+		}
+		params.Set("continuation_token", page.NextContinuationToken)
+	}
+}
+
+// ListDeleteMarkers returns only the delete markers under prefix, for
+// example to remove them permanently and reclaim space.
+func (c *Client) ListDeleteMarkers(bucket string, prefix *string) ([]ObjectVersion, error) {
+	versions, err := c.ListObjectVersions(bucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	markers := versions[:0]
+	for _, v := range versions {
+		if v.IsDeleteMarker {
+			markers = append(markers, v)
+		}
+	}
+	return markers, nil
+}
+
+func (c *Client) listObjectVersions(bucket string, params url.Values) (*listObjectVersionsResponse, error) {
+	urlPath := c.bucketURL(bucket) + "/versions"
+	if len(params) > 0 {
+		urlPath += "?" + params.Encode()
+	}
+	req, err := http.NewRequest("GET", urlPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do("ListObjectVersions", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.newError(resp)
+	}
+
+	var result listObjectVersionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func supportsVersioning(s *ServerCapabilities) bool {
+	return s.Versioning
+}
@@ -0,0 +1,43 @@
+package objectstorage
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListObjectVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/buckets/test-bucket/versions", r.URL.Path)
+		assert.Equal(t, "logs/", r.URL.Query().Get("prefix"))
+		if r.URL.Query().Get("continuation_token") == "" {
+			fmt.Fprint(w, `{"versions":[{"key":"logs/a","version_id":"v2","is_latest":true,"is_delete_marker":true},{"key":"logs/a","version_id":"v1","size":5,"etag":"e1"}],"next_continuation_token":"page-2"}`)
+			return
+		}
+		// S3-style: delete markers in their own list.
+		fmt.Fprint(w, `{"Versions":[{"Key":"logs/b","VersionId":"v1","IsLatest":true,"Size":3}],"DeleteMarkers":[{"Key":"logs/c","VersionId":"v4","IsLatest":true}]}`)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	prefix := "logs/"
+	versions, err := client.ListObjectVersions("test-bucket", &prefix)
+	require.NoError(t, err)
+	require.Len(t, versions, 4)
+	assert.True(t, versions[0].IsDeleteMarker)
+	assert.False(t, versions[1].IsDeleteMarker)
+	assert.Equal(t, uint64(5), versions[1].Size)
+	assert.Equal(t, "logs/b", versions[2].Key)
+	assert.False(t, versions[2].IsDeleteMarker)
+
+	markers, err := client.ListDeleteMarkers("test-bucket", &prefix)
+	require.NoError(t, err)
+	require.Len(t, markers, 2)
+	assert.Equal(t, "v2", markers[0].VersionID)
+	assert.Equal(t, "logs/c", markers[1].Key)
+	assert.True(t, markers[1].IsLatest)
+}
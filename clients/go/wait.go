@@ -0,0 +1,35 @@
+package objectstorage
+
+import (
+	"context"
+	"time"
+)
+
+// WaitForObject polls bucket/key with HEAD requests every poll interval until
+// the object exists, returning its metadata, or until ctx is done. Errors
+// other than 404 Not Found end the wait.
+func (c *Client) WaitForObject(ctx context.Context, bucket, key string, poll time.Duration) (*ObjectMetadata, error) {
+	return c.WaitForObjectFunc(ctx, bucket, key, poll, nil)
+}
+
+// WaitForObjectFunc is WaitForObject that also keeps polling while ready
+// returns false, for example until the object has reached a minimum size and
+// is therefore fully written. A nil ready accepts any existing object.
+func (c *Client) WaitForObjectFunc(ctx context.Context, bucket, key string, poll time.Duration, ready func(*ObjectMetadata) bool) (*ObjectMetadata, error) {
+	for {
+		obj, err := c.headObject(ctx, "WaitForObject", bucket, key)
+		switch {
+		case err == nil:
+			if ready == nil || ready(obj) {
+				return obj, nil
+			}
+		case isNotFound(err):
+		default:
+			return nil, err
+		}
+
+		if err := c.sleep(ctx, poll); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package objectstorage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForObject(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "HEAD", r.Method)
+		n := hits.Add(1)
+		if n < 3 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// The object grows as the writer progresses.
+		w.Header().Set("Content-Length", strconv.Itoa(int(n)*100))
+	}))
+	defer server.Close()
+
+	clock := newFakeClock()
+	client := NewClient(server.URL, WithClock(clock))
+	obj, err := client.WaitForObject(context.Background(), "test-bucket", "test-key", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(300), obj.Size)
+	assert.Equal(t, []time.Duration{time.Second, time.Second}, clock.sleeps)
+
+	obj, err = client.WaitForObjectFunc(context.Background(), "test-bucket", "test-key", time.Second, func(obj *ObjectMetadata) bool {
+		return obj.Size >= 600
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(600), obj.Size)
+}
+
+func TestWaitForObjectErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/buckets/test-bucket/objects/forbidden" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	_, err := client.WaitForObject(context.Background(), "test-bucket", "forbidden", time.Millisecond)
+	var objErr *Error
+	require.ErrorAs(t, err, &objErr)
+	assert.Equal(t, http.StatusForbidden, objErr.StatusCode)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.WaitForObject(ctx, "test-bucket", "missing", time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}